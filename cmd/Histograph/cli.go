@@ -0,0 +1,560 @@
+// cli.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/archive"
+	"github.com/akshatsrivastava11/Histograph/internals/export"
+	"github.com/akshatsrivastava11/Histograph/internals/graph"
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+	"github.com/akshatsrivastava11/Histograph/internals/render"
+	"github.com/akshatsrivastava11/Histograph/internals/report"
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	"github.com/akshatsrivastava11/Histograph/internals/urlnorm"
+)
+
+// command is a histograph subcommand.
+type command struct {
+	name    string
+	args    string // positional arguments, for the usage line
+	summary string
+	formats []string // accepted --format values, the first is the default
+	flags   func(fs *flag.FlagSet, c *cliConfig)
+	run     func(c *cliConfig) error
+}
+
+// commands lists the subcommands in the order they are shown in the usage.
+var commands = []command{
+	{
+		name: "tui", summary: "browse history interactively (the default)",
+		flags: func(fs *flag.FlagSet, c *cliConfig) {
+			fs.DurationVar(&c.sessionGap, "session-gap", stats.DefaultSessionGap, "inactivity that ends a browsing session in the Sessions view")
+			fs.StringVar(&c.trackingParams, "tracking-params", strings.Join(urlnorm.DefaultTrackingParams, ","),
+				"comma-separated query parameters dropped before URLs are compared, * matches any suffix")
+			fs.BoolVar(&c.urlRules.KeepFragment, "keep-fragments", false, "count URLs that differ only in their #fragment as different pages")
+		},
+		run: runTUI,
+	},
+	{name: "list", summary: "print visits, most recent first", formats: []string{"text", "json", "ndjson", "csv"}, run: runList},
+	{name: "stats", summary: "print summary statistics", formats: []string{"text", "json"}, run: runStats},
+	{
+		name: "top", summary: "print the most visited domains", formats: []string{"text", "json"},
+		flags: func(fs *flag.FlagSet, c *cliConfig) {
+			fs.IntVar(&c.top, "n", 10, "number of domains to print")
+		},
+		run: runTop,
+	},
+	{name: "search", args: "<query>", summary: "search archived titles, URLs and domains (substring matching unless built with FTS5)", formats: []string{"text", "json", "ndjson", "csv"}, run: runSearch},
+	{
+		name: "export", summary: "write visits as JSON, NDJSON or CSV for other tools", formats: []string{"json", "ndjson", "csv"},
+		flags: func(fs *flag.FlagSet, c *cliConfig) {
+			fs.StringVar(&c.output, "o", "-", "file to write to, - for stdout")
+		},
+		run: runExport,
+	},
+	{
+		name: "report", summary: "write a self-contained HTML report",
+		flags: func(fs *flag.FlagSet, c *cliConfig) {
+			fs.StringVar(&c.output, "html", "", "HTML file to write the report to, - for stdout")
+		},
+		run: runReport,
+	},
+	{
+		name: "graph", summary: "write the navigation graph from referrers as DOT, GraphML or JSON", formats: []string{"dot", "graphml", "json"},
+		flags: func(fs *flag.FlagSet, c *cliConfig) {
+			fs.StringVar(&c.level, "level", string(graph.Domains), "graph nodes: domain or page")
+			fs.StringVar(&c.output, "o", "-", "file to write to, - for stdout")
+		},
+		run: runGraph,
+	},
+	{name: "sync", summary: "pull new visits from every detected browser into the archive", run: runSync},
+}
+
+// cliConfig holds the parsed flags and arguments of a subcommand.
+type cliConfig struct {
+	choice      render.BrowserChoice // every detected browser unless --browser is given
+	browserSet  bool                 // whether --browser named a single browser
+	opts        parse.Options
+	loc         *time.Location
+	format      string
+	archivePath string // empty when the archive is disabled
+	top         int
+	sessionGap  time.Duration
+	// trackingParams and urlRules configure URL canonicalisation in the TUI
+	trackingParams string
+	urlRules       urlnorm.Rules
+	output         string
+	level          string
+	args           []string
+}
+
+// usageError is an error caused by invalid command line input. It exits with
+// status 2 instead of 1.
+type usageError struct{ error }
+
+func usageErrorf(format string, a ...any) error {
+	return usageError{fmt.Errorf(format, a...)}
+}
+
+// runCLI runs the subcommand named by args[0], or the TUI when args holds no
+// subcommand, and returns the process exit code.
+func runCLI(args []string) int {
+	name := "tui"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+
+	i := slices.IndexFunc(commands, func(c command) bool { return c.name == name })
+	if i < 0 {
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q\n\n", name)
+		printUsage(os.Stderr)
+		return 2
+	}
+	cmd := commands[i]
+
+	c, err := parseCommand(cmd, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err == nil {
+		err = cmd.run(c)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: histograph [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'histograph <command> -h' for the flags of a command.")
+}
+
+// parseCommand parses the flags shared by every command plus cmd's own.
+// Flags may appear before, between or after positional arguments.
+func parseCommand(cmd command, args []string) (*cliConfig, error) {
+	c := &cliConfig{}
+	fs := flag.NewFlagSet("histograph "+cmd.name, flag.ContinueOnError)
+	// Parse errors are reported by runCLI, so only -h prints the flags.
+	fs.SetOutput(io.Discard)
+
+	browser := fs.String("browser", "", "only read this browser, e.g. Chrome or Firefox (default: every detected browser)")
+	profile := fs.String("profile", "", "comma-separated profiles of --browser to read (default: its default profile)")
+	limit := fs.Int("limit", 5000, "maximum number of visits to read per profile, and in total from the archive (0 for no limit)")
+	since := fs.String("since", "", "only read visits from this time on (YYYY-MM-DD, RFC 3339 or a duration like 7d)")
+	until := fs.String("until", "", "only read visits before this time (YYYY-MM-DD, RFC 3339 or a duration like 7d)")
+	domain := fs.String("domain", "", "only read visits to this domain and its subdomains")
+	tz := fs.String("tz", os.Getenv("HISTOGRAPH_TZ"), "time zone for displaying and grouping visits: local, utc or an IANA name (default local, or $HISTOGRAPH_TZ)")
+	noArchive := fs.Bool("no-archive", false, "read only from the browser, without storing visits in or reading them from the local archive")
+	if len(cmd.formats) > 0 {
+		fs.StringVar(&c.format, "format", cmd.formats[0], "output format: "+strings.Join(cmd.formats, ", "))
+	}
+	if cmd.flags != nil {
+		cmd.flags(fs, c)
+	}
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fs.SetOutput(os.Stderr)
+				fs.Usage()
+				return nil, err
+			}
+			return nil, usageError{err}
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		// Everything after "--" is positional.
+		if len(args) > len(fs.Args()) && args[len(args)-len(fs.Args())-1] == "--" {
+			c.args = append(c.args, fs.Args()...)
+			break
+		}
+		c.args = append(c.args, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(cmd.formats) > 0 && !slices.Contains(cmd.formats, c.format) {
+		return nil, usageErrorf("unsupported format %q for %s, use %s", c.format, cmd.name, strings.Join(cmd.formats, ", "))
+	}
+	if cmd.args == "" && len(c.args) > 0 {
+		return nil, usageErrorf("%s takes no arguments, got %q", cmd.name, strings.Join(c.args, " "))
+	}
+
+	var err error
+	if c.loc, err = parseLocation(*tz); err != nil {
+		return nil, usageError{err}
+	}
+
+	now := time.Now()
+	c.opts = parse.Options{Limit: *limit, Domain: *domain}
+	if c.opts.Since, err = parseTimeFlag(*since, now, c.loc, false); err != nil {
+		return nil, usageError{err}
+	}
+	if c.opts.Until, err = parseTimeFlag(*until, now, c.loc, true); err != nil {
+		return nil, usageError{err}
+	}
+
+	if c.choice, err = resolveChoice(*browser, *profile); err != nil {
+		return nil, err
+	}
+	c.browserSet = c.choice.Browser != parse.AllBrowsers
+
+	if !*noArchive {
+		if c.archivePath, err = archive.DefaultPath(); err != nil {
+			debugLog("Archive disabled: %v", err)
+		}
+	}
+	return c, nil
+}
+
+// resolveChoice turns the --browser and --profile flags into a BrowserChoice.
+// Browser names are matched case-insensitively; "all" or no browser selects
+// every detected browser.
+func resolveChoice(browser, profile string) (render.BrowserChoice, error) {
+	if browser == "" || strings.EqualFold(browser, "all") {
+		if profile != "" {
+			return render.BrowserChoice{}, usageErrorf("--profile needs --browser")
+		}
+		return render.BrowserChoice{Browser: parse.AllBrowsers}, nil
+	}
+
+	var src parse.HistorySource
+	var names []string
+	for _, s := range parse.Sources() {
+		if strings.EqualFold(s.Name(), browser) {
+			src = s
+		}
+		names = append(names, s.Name())
+	}
+	if src == nil {
+		return render.BrowserChoice{}, usageErrorf("unknown browser %q, use one of %s", browser, strings.Join(names, ", "))
+	}
+
+	choice := render.BrowserChoice{Browser: src.Name()}
+	if profile == "" {
+		return choice, nil
+	}
+
+	profiles, err := src.Profiles()
+	if err != nil {
+		return render.BrowserChoice{}, err
+	}
+	for _, name := range strings.Split(profile, ",") {
+		name = strings.TrimSpace(name)
+		i := slices.IndexFunc(profiles, func(p parse.Profile) bool { return p.Name == name })
+		if i < 0 {
+			return render.BrowserChoice{}, usageErrorf("%s has no profile %q", src.Name(), name)
+		}
+		choice.Profiles = append(choice.Profiles, profiles[i])
+	}
+	return choice, nil
+}
+
+// entries loads the history selected by the command line flags.
+func (c *cliConfig) entries() ([]types.VisitEntry, error) {
+	result := loadHistory(c.choice, c.opts, c.archivePath)
+	return result.entries, result.err
+}
+
+func runTUI(c *cliConfig) error {
+	// Without --browser, start at the browser menu
+	var choice render.BrowserChoice
+	if c.browserSet {
+		choice = c.choice
+	}
+	load := func(choice render.BrowserChoice) ([]types.VisitEntry, error) {
+		result := loadHistory(choice, c.opts, c.archivePath)
+		return result.entries, result.err
+	}
+
+	for _, param := range strings.Split(c.trackingParams, ",") {
+		if param = strings.TrimSpace(param); param != "" {
+			c.urlRules.TrackingParams = append(c.urlRules.TrackingParams, param)
+		}
+	}
+	viewer := render.ViewerOptions{Location: c.loc, SessionGap: c.sessionGap, URLRules: &c.urlRules}
+	if err := render.Run(load, choice, viewer); err != nil {
+		return fmt.Errorf("failed to run program: %w", err)
+	}
+	return nil
+}
+
+func runList(c *cliConfig) error {
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	return c.printEntries(entries)
+}
+
+func runStats(c *cliConfig) error {
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	s := stats.Summarize(entries)
+	if c.format == "json" {
+		return printJSON(s)
+	}
+
+	fmt.Printf("Entries:         %d\n", s.Entries)
+	fmt.Printf("Visits:          %d\n", s.Visits)
+	fmt.Printf("Unique URLs:     %d\n", s.UniqueURLs)
+	fmt.Printf("Unique domains:  %d\n", s.Domains)
+	fmt.Printf("First visit:     %s\n", s.FirstVisit.In(c.loc).Format("2006-01-02 15:04"))
+	fmt.Printf("Last visit:      %s\n", s.LastVisit.In(c.loc).Format("2006-01-02 15:04"))
+	if len(s.Browsers) > 1 {
+		fmt.Printf("Browsers:        %s\n", stats.Breakdown(s.Browsers))
+	}
+	return nil
+}
+
+func runTop(c *cliConfig) error {
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	sites := stats.TopSites(entries)
+	if c.top > 0 && len(sites) > c.top {
+		sites = sites[:c.top]
+	}
+	if c.format == "json" {
+		return printJSON(sites)
+	}
+
+	for i, site := range sites {
+		fmt.Printf("%3d. %-40s %6d visits %6d entries\n", i+1, site.Domain, site.Visits, site.Entries)
+	}
+	return nil
+}
+
+// runSearch syncs the selected browsers into the archive, then prints the
+// archived visits matching the query, best match first.
+func runSearch(c *cliConfig) error {
+	text := strings.Join(c.args, " ")
+	if strings.TrimSpace(text) == "" {
+		return usageErrorf("search needs a query")
+	}
+	if c.archivePath == "" {
+		return fmt.Errorf("search needs the archive, drop --no-archive")
+	}
+
+	a, err := archive.Open(c.archivePath)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	if !a.FullText() {
+		fmt.Fprintln(os.Stderr, "Note: built without FTS5, so search falls back to substring matching. Build with -tags sqlite_fts5 for ranked word search.")
+	}
+
+	sources, err := c.sources()
+	if err != nil {
+		return err
+	}
+	for _, r := range a.Sync(sources) {
+		if r.Err != nil {
+			debugLog("Failed to sync %s (%s): %v", r.Browser, r.Profile, r.Err)
+		}
+	}
+
+	q := archive.Query{Options: c.opts}
+	if c.browserSet {
+		q.Browser = c.choice.Browser
+		for _, p := range c.choice.Profiles {
+			q.Profiles = append(q.Profiles, p.Dir)
+		}
+	}
+	entries, err := a.Search(text, q)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 && c.format == "text" {
+		fmt.Println("No matching visits.")
+		return nil
+	}
+	return c.printEntries(entries)
+}
+
+func runExport(c *cliConfig) error {
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	return writeOutput(c.output, fmt.Sprintf("%d visits", len(entries)), func(w io.Writer) error {
+		return export.Write(w, entries, export.Format(c.format))
+	})
+}
+
+// runReport writes the statistics of the selected visits to an HTML file
+// that opens offline.
+func runReport(c *cliConfig) error {
+	if c.output == "" {
+		return usageErrorf("report needs --html <file>")
+	}
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	opts := report.Options{Title: "Histograph report: " + c.choice.String(), Location: c.loc}
+	return writeOutput(c.output, fmt.Sprintf("a report of %d visits", len(entries)), func(w io.Writer) error {
+		return report.Write(w, entries, opts)
+	})
+}
+
+// runGraph writes the navigation graph of the selected visits.
+func runGraph(c *cliConfig) error {
+	level := graph.Level(c.level)
+	if level != graph.Pages && level != graph.Domains {
+		return usageErrorf("unsupported level %q, use %s or %s", c.level, graph.Domains, graph.Pages)
+	}
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	edges := graph.Build(entries).Edges(level)
+	return writeOutput(c.output, fmt.Sprintf("a graph of %d links", len(edges)), func(w io.Writer) error {
+		switch c.format {
+		case "graphml":
+			return graph.WriteGraphML(w, edges)
+		case "json":
+			if edges == nil {
+				edges = []graph.Edge{}
+			}
+			return writeJSON(w, edges)
+		}
+		return graph.WriteDOT(w, edges)
+	})
+}
+
+// writeOutput calls write with stdout for path "-", or with the file at path.
+// what describes the output in the message printed after writing a file.
+func writeOutput(path, what string, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s to %s\n", what, path)
+	return nil
+}
+
+// runSync pulls new visits from the selected browsers into the archive and
+// prints how many were added per browser profile. It is meant to be run from
+// cron or a systemd timer.
+func runSync(c *cliConfig) error {
+	if c.archivePath == "" {
+		return fmt.Errorf("sync needs the archive, drop --no-archive")
+	}
+	if len(c.choice.Profiles) > 0 {
+		return usageErrorf("sync reads every profile, drop --profile")
+	}
+
+	a, err := archive.Open(c.archivePath)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	sources, err := c.sources()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range a.Sync(sources) {
+		name := r.Browser
+		if r.Profile != "" {
+			name += " (" + r.Profile + ")"
+		}
+		if r.Err != nil {
+			failed++
+			fmt.Printf("%s: error: %v\n", name, r.Err)
+			continue
+		}
+		fmt.Printf("%s: %d new visits\n", name, r.Added)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d profiles could not be synced", failed)
+	}
+	return nil
+}
+
+// sources returns the source chosen with --browser, or every detected one.
+func (c *cliConfig) sources() ([]parse.HistorySource, error) {
+	if c.browserSet {
+		src, _ := parse.Lookup(c.choice.Browser)
+		return []parse.HistorySource{src}, nil
+	}
+	sources := parse.DetectedSources()
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no browser history detected")
+	}
+	return sources, nil
+}
+
+// printEntries prints entries in the chosen format. The text format is one
+// tab-separated line per visit: time, browser, title and URL; the others are
+// the export formats.
+func (c *cliConfig) printEntries(entries []types.VisitEntry) error {
+	if c.format != "text" {
+		return export.Write(os.Stdout, entries, export.Format(c.format))
+	}
+	for _, e := range entries {
+		fmt.Printf("%s\t%s\t%s\t%s\n", e.VisitTime.In(c.loc).Format("2006-01-02 15:04:05"), e.Browser, e.Title, e.URL)
+	}
+	return nil
+}
+
+func printJSON(v any) error {
+	return writeJSON(os.Stdout, v)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
@@ -5,96 +5,27 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/akshatsrivastava11/Histograph/internals/archive"
 	"github.com/akshatsrivastava11/Histograph/internals/parse"
 	"github.com/akshatsrivastava11/Histograph/internals/render"
 	"github.com/akshatsrivastava11/Histograph/internals/types"
-	"github.com/charmbracelet/bubbles/spinner"
-	"github.com/charmbracelet/bubbles/viewport"
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
-// Styles
-var (
-	cardStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("62")).
-			Padding(1, 2).
-			MarginTop(1).
-			MarginBottom(1)
-
-	titleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("205")).
-			Bold(true).
-			MarginBottom(1)
-
-	successStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("46")).
-			Bold(true)
-
-	errorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
-			Bold(true)
-
-	infoStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("39")).
-			Bold(true)
-
-	promptStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("228")).
-			Bold(true)
-)
-
-// Model for handling browser history processing
-type historyModel struct {
-	browserChoice string
-	viewport      viewport.Model
-	content       string
-	done          bool
-	err           error
-	spinner       spinner.Model
-	loading       bool
-}
-
-func newHistoryModel(browserChoice string) historyModel {
-	vp := viewport.New(80, 20)
-	sp := spinner.New()
-	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
-
-	content := titleStyle.Render("🔍 Fetching " + browserChoice + " history...")
-	// timer.New(10000).Init()
-	return historyModel{
-		browserChoice: browserChoice,
-		viewport:      vp,
-		content:       content,
-		done:          false,
-		spinner:       sp,
-		loading:       true,
+// loadHistory reads the history of the chosen browser and profiles, or of
+// every detected browser, through the archive unless archivePath is empty.
+func loadHistory(browserChoice render.BrowserChoice, opts parse.Options, archivePath string) historyResult {
+	if browserChoice.Browser == parse.AllBrowsers {
+		return processAllHistory(opts, archivePath)
 	}
-}
-
-func (m historyModel) Init() tea.Cmd {
-	return tea.Batch(
-		tea.EnterAltScreen,
-		m.spinner.Tick,
-		processHistoryCmd(m.browserChoice),
-	)
-	// return nil
-}
-
-// Command to process browser history
-func processHistoryCmd(browserChoice string) tea.Cmd {
-	return func() tea.Msg {
-		switch browserChoice {
-		case "Firefox":
-			return processFirefoxHistory()
-		case "Chrome":
-			return processChromeHistory()
-		default:
-			return historyResult{err: fmt.Errorf("invalid browser selection")}
-		}
+	src, ok := parse.Lookup(browserChoice.Browser)
+	if !ok {
+		return historyResult{err: fmt.Errorf("invalid browser selection")}
 	}
+	return processHistory(src, opts, browserChoice.Profiles, archivePath)
 }
 
 type historyResult struct {
@@ -103,15 +34,31 @@ type historyResult struct {
 	err     error
 }
 
-func processChromeHistory() historyResult {
-	historyData, err := parse.ParseChromeHistory()
-	if err != nil {
-		return historyResult{err: err}
+func processHistory(src parse.HistorySource, opts parse.Options, profiles []parse.Profile, archivePath string) historyResult {
+	if len(profiles) == 0 {
+		all, err := src.Profiles()
+		if err != nil {
+			return historyResult{err: err}
+		}
+		profile, err := parse.DefaultProfile(all)
+		if err != nil {
+			return historyResult{err: fmt.Errorf("no %s profile found: %w", src.Name(), err)}
+		}
+		profiles = []parse.Profile{profile}
+	}
+
+	q := archive.Query{Options: opts, Browser: src.Name()}
+	for _, p := range profiles {
+		q.Profiles = append(q.Profiles, p.Dir)
 	}
+
+	historyData, err := parse.Parse(src, opts, profiles...)
+	historyData = loadFromArchive(archivePath, historyData, q)
 	if len(historyData) == 0 {
-		return historyResult{
-			err: fmt.Errorf("no Chrome history found or unable to access Chrome history"),
+		if err == nil {
+			err = fmt.Errorf("no %s history found or unable to access %s history", src.Name(), src.Name())
 		}
+		return historyResult{err: err}
 	}
 
 	return historyResult{
@@ -120,15 +67,18 @@ func processChromeHistory() historyResult {
 	}
 }
 
-func processFirefoxHistory() historyResult {
-	historyData, err := parse.ParseFirefoxHistory()
-	if err != nil {
-		return historyResult{err: err}
-	}
+func processAllHistory(opts parse.Options, archivePath string) historyResult {
+	historyData, err := parse.ParseAll(opts)
+	historyData = loadFromArchive(archivePath, historyData, archive.Query{Options: opts})
 	if len(historyData) == 0 {
-		return historyResult{
-			err: fmt.Errorf("no Firefox history found or unable to access Firefox history"),
+		if err == nil {
+			err = fmt.Errorf("no history found in any detected browser")
 		}
+		return historyResult{err: err}
+	}
+	if err != nil {
+		// Some browsers failed but others had history, show what we have.
+		debugLog("Some browsers could not be read: %v", err)
 	}
 
 	return historyResult{
@@ -137,83 +87,30 @@ func processFirefoxHistory() historyResult {
 	}
 }
 
-func (m historyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
-			if m.done {
-				return m, tea.Quit
-			}
-			if m.err != nil {
-				// Retry fetching history
-				m.done = false
-				m.err = nil
-				m.loading = true
-				m.content = titleStyle.Render("🔍 Fetching " + m.browserChoice + " history...")
-				return m, tea.Batch(m.spinner.Tick, processHistoryCmd(m.browserChoice))
-			}
-		case "q", "ctrl+c":
-			return m, tea.Quit
-		}
-
-	case historyResult:
-		if msg.err != nil {
-			m.content = errorStyle.Render("❌ Error: "+msg.err.Error()) + "\n\n" +
-				infoStyle.Render("Make sure your browser is closed and try again. If you see a 'database is locked' error, close your browser and retry.") + "\n\n" +
-				promptStyle.Render("Press 'q' to quit or 'enter' to retry")
-			m.err = msg.err
-			m.done = true
-			m.loading = false
-		} else {
-			m.content = successStyle.Render(fmt.Sprintf("✅ Found %d history entries", msg.count)) + "\n\n" +
-				infoStyle.Render("🚀 Starting "+m.browserChoice+" History Visualizer...") + "\n\n" +
-				promptStyle.Render("Press 'enter' to continue or 'q' to quit")
-			// timer.New(20000000).Init()
-			// Start the visualizer
-			go func() {
-				err := render.RunChromeHistoryViewer(msg.entries)
-				if err != nil {
-					debugLog("Error running history visualizer: %v", err)
-				}
-			}()
-
-			m.done = true
-			m.loading = false
-		}
-		m.viewport.SetContent(m.content)
-		return m, nil
-
-	case tea.WindowSizeMsg:
-		m.viewport.Width = msg.Width
-		m.viewport.Height = msg.Height
-		m.viewport.SetContent(m.content)
-		return m, nil
-
-	case spinner.TickMsg:
-		if m.loading {
-			var cmd tea.Cmd
-			m.spinner, cmd = m.spinner.Update(msg)
-			return m, cmd
-		}
+// loadFromArchive stores freshly parsed entries in the archive and reads back
+// everything it holds for q, so the views cover visits the browser has since
+// expired. Archived entries are returned even when the browser itself could
+// not be read. With no archive path, or if the archive fails, the parsed
+// entries are returned unchanged.
+func loadFromArchive(archivePath string, parsed []types.VisitEntry, q archive.Query) []types.VisitEntry {
+	if archivePath == "" {
+		return parsed
 	}
 
-	var cmd tea.Cmd
-	m.viewport, cmd = m.viewport.Update(msg)
-	return m, cmd
-}
-
-func (m historyModel) View() string {
-	if m.done {
-		footer := "\n" + infoStyle.Render("✨ Thanks for using Histograph!")
-		return cardStyle.Render(m.viewport.View() + footer)
+	a, err := archive.Open(archivePath)
+	if err != nil {
+		debugLog("Archive unavailable: %v", err)
+		return parsed
 	}
+	defer a.Close()
 
-	if m.loading {
-		spinnerView := m.spinner.View() + " " + titleStyle.Render("Fetching "+m.browserChoice+" history...")
-		return cardStyle.Render(spinnerView)
+	archived, added, err := a.Refresh(parsed, q)
+	if err != nil {
+		debugLog("Failed to archive history: %v", err)
+		return parsed
 	}
-	return cardStyle.Render(m.viewport.View())
+	debugLog("Archived %d new visits", added)
+	return archived
 }
 
 // debugLog prints debug output only if HISTOGRAPH_DEBUG=1 is set in the environment.
@@ -223,20 +120,49 @@ func debugLog(format string, v ...interface{}) {
 	}
 }
 
-func main() {
-	// Get user's browser choice
-	choice, err := render.GetUserBrowserChoice()
+// parseLocation resolves the --tz value: "local", "utc" or an IANA zone name.
+func parseLocation(name string) (*time.Location, error) {
+	switch strings.ToLower(name) {
+	case "", "local":
+		return time.Local, nil
+	case "utc":
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
 	if err != nil {
-		fmt.Println("Error getting browser choice:", err)
-		return
+		return nil, fmt.Errorf("invalid time zone %q: use local, utc or an IANA name like Europe/Berlin", name)
 	}
+	return loc, nil
+}
 
-	// Create and run the history processing model
-	model := newHistoryModel(choice)
-	// time.Sleep(3 * time.Second)
-	prog := tea.NewProgram(model)
-
-	if _, err := prog.Run(); err != nil {
-		fmt.Println("Error running program:", err)
+// parseTimeFlag parses a --since/--until value: a date (2006-01-02) in loc, an
+// RFC 3339 timestamp, or a duration back from now such as 7d or 12h. A bare date
+// passed to --until covers the whole day.
+func parseTimeFlag(value string, now time.Time, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
 	}
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use YYYY-MM-DD, RFC 3339 or a duration like 7d", value)
+}
+
+func main() {
+	os.Exit(runCLI(os.Args[1:]))
 }
@@ -0,0 +1,301 @@
+// Package archive keeps Histograph's own copy of browser history, so visits
+// survive after the browser expires them.
+package archive
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Visits and sync state are keyed on the profile directory rather than its
+// display name, so renaming a profile does not start a second copy of its
+// history. The display name is kept alongside and follows renames.
+const schema = `
+CREATE TABLE IF NOT EXISTS visits (
+	browser           TEXT    NOT NULL,
+	profile           TEXT    NOT NULL,
+	profile_dir       TEXT    NOT NULL,
+	visit_id          INTEGER NOT NULL,
+	url               TEXT    NOT NULL,
+	title             TEXT    NOT NULL,
+	visit_count       INTEGER NOT NULL,
+	visit_time        INTEGER NOT NULL, -- microseconds since the Unix epoch
+	url_id            INTEGER NOT NULL,
+	typed_count       INTEGER NOT NULL,
+	transition        TEXT    NOT NULL,
+	referrer_visit_id INTEGER NOT NULL,
+	duration          INTEGER NOT NULL, -- microseconds
+	PRIMARY KEY (browser, profile_dir, visit_id)
+);
+CREATE INDEX IF NOT EXISTS visits_visit_time ON visits (visit_time);
+CREATE TABLE IF NOT EXISTS sync_state (
+	browser         TEXT    NOT NULL,
+	profile_dir     TEXT    NOT NULL,
+	last_visit_time INTEGER NOT NULL, -- microseconds since the Unix epoch
+	synced_at       INTEGER NOT NULL, -- microseconds since the Unix epoch
+	PRIMARY KEY (browser, profile_dir)
+);
+`
+
+// Archive is a Histograph-owned SQLite database of visits from every source.
+type Archive struct {
+	db  *sql.DB
+	fts bool // whether the FTS5 search index is available
+}
+
+// Query selects entries from the archive.
+type Query struct {
+	// Options filters by time window and domain. Limit caps the total number of
+	// entries rather than the number per profile.
+	parse.Options
+	// Browser keeps only entries from this source. Empty means every source.
+	Browser string
+	// Profiles keeps only entries from the profiles in these directories. Empty
+	// means every profile.
+	Profiles []string
+}
+
+// DefaultPath returns the archive location: HISTOGRAPH_ARCHIVE_PATH if set,
+// otherwise histograph/archive.db under the XDG data directory.
+func DefaultPath() (string, error) {
+	// Allow override via environment variable
+	if envPath := os.Getenv("HISTOGRAPH_ARCHIVE_PATH"); envPath != "" {
+		return envPath, nil
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "histograph", "archive.db"), nil
+}
+
+// Open opens the archive at path, creating it if needed.
+func Open(path string) (*Archive, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create archive schema: %w", err)
+	}
+
+	a := &Archive{db: db}
+	if a.fts, err = a.initSearch(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// Close closes the archive.
+func (a *Archive) Close() error {
+	return a.db.Close()
+}
+
+// Ingest stores entries in the archive and returns how many were new. Entries
+// are deduplicated on browser, profile directory and visit ID; for visits
+// already archived only the title and visit count are refreshed. Entries
+// without a ProfileDir are keyed on their profile name.
+func (a *Archive) Ingest(entries []types.VisitEntry) (int, error) {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start archive transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := renameProfiles(tx, entries); err != nil {
+		return 0, err
+	}
+
+	insert, err := tx.Prepare(`
+		INSERT OR IGNORE INTO visits (browser, profile, profile_dir, visit_id, url, title, visit_count,
+			visit_time, url_id, typed_count, transition, referrer_visit_id, duration)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare archive insert: %w", err)
+	}
+	defer insert.Close()
+
+	// Only rows whose title changed are returned, so only those are re-indexed.
+	retitle, err := tx.Prepare(`
+		UPDATE visits SET title = ?
+		WHERE browser = ? AND profile_dir = ? AND visit_id = ? AND title != ?
+		RETURNING rowid`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare archive update: %w", err)
+	}
+	defer retitle.Close()
+
+	recount, err := tx.Prepare(`
+		UPDATE visits SET visit_count = ?
+		WHERE browser = ? AND profile_dir = ? AND visit_id = ? AND visit_count < ?`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare archive update: %w", err)
+	}
+	defer recount.Close()
+
+	added := 0
+	for _, e := range entries {
+		dir := e.Key().ProfileDir
+		res, err := insert.Exec(e.Browser, e.Profile, dir, e.VisitID, e.URL, e.Title, e.VisitCount,
+			e.VisitTime.UnixMicro(), e.URLID, e.TypedCount, string(e.Transition),
+			e.ReferrerVisitID, e.Duration.Microseconds())
+		if err != nil {
+			return 0, fmt.Errorf("failed to archive visit: %w", err)
+		}
+
+		var rowid int64
+		if n, _ := res.RowsAffected(); n > 0 {
+			added++
+			rowid, err = res.LastInsertId()
+			if err != nil {
+				return 0, fmt.Errorf("failed to archive visit: %w", err)
+			}
+		} else {
+			if _, err := recount.Exec(e.VisitCount, e.Browser, dir, e.VisitID, e.VisitCount); err != nil {
+				return 0, fmt.Errorf("failed to refresh archived visit: %w", err)
+			}
+			err := retitle.QueryRow(e.Title, e.Browser, dir, e.VisitID, e.Title).Scan(&rowid)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return 0, fmt.Errorf("failed to refresh archived visit: %w", err)
+			}
+		}
+
+		if a.fts {
+			if err := indexVisit(tx, rowid, e.Title, e.URL); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit archive transaction: %w", err)
+	}
+	return added, nil
+}
+
+// renameProfiles brings the archived display names of the profiles in entries
+// up to date.
+func renameProfiles(tx *sql.Tx, entries []types.VisitEntry) error {
+	type profile struct{ browser, dir, name string }
+	seen := make(map[profile]bool)
+	for _, e := range entries {
+		p := profile{e.Browser, e.Key().ProfileDir, e.Profile}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		_, err := tx.Exec(`
+			UPDATE visits SET profile = ? WHERE browser = ? AND profile_dir = ? AND profile != ?`,
+			p.name, p.browser, p.dir, p.name)
+		if err != nil {
+			return fmt.Errorf("failed to update archived profile %s: %w", p.name, err)
+		}
+	}
+	return nil
+}
+
+// Refresh ingests freshly parsed entries and returns the archived entries
+// matching q, along with how many of the entries were new.
+func (a *Archive) Refresh(parsed []types.VisitEntry, q Query) ([]types.VisitEntry, int, error) {
+	added := 0
+	if len(parsed) > 0 {
+		var err error
+		if added, err = a.Ingest(parsed); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	entries, err := a.Entries(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, added, nil
+}
+
+// Entries returns the archived entries matching q, most recent first.
+func (a *Archive) Entries(q Query) ([]types.VisitEntry, error) {
+	conds, args := q.conditions()
+	return a.queryEntries("visits", conds, args, "visits.visit_time DESC", q.Limit)
+}
+
+// conditions returns the WHERE conditions and arguments selecting q's entries.
+func (q Query) conditions() ([]string, []any) {
+	conds, args := q.Conditions("visits.visit_time", "visits.url", time.Time.UnixMicro)
+	if q.Browser != "" {
+		conds = append(conds, "visits.browser = ?")
+		args = append(args, q.Browser)
+	}
+	if len(q.Profiles) > 0 {
+		conds = append(conds, "visits.profile_dir IN (?"+strings.Repeat(", ?", len(q.Profiles)-1)+")")
+		for _, p := range q.Profiles {
+			args = append(args, p)
+		}
+	}
+	return conds, args
+}
+
+// queryEntries selects visits from the from clause. Ties on order are broken
+// by origin and visit ID, so results are deterministic.
+func (a *Archive) queryEntries(from string, conds []string, args []any, order string, limit int) ([]types.VisitEntry, error) {
+	query := `
+		SELECT visits.browser, visits.profile, visits.profile_dir, visits.visit_id, visits.url, visits.title,
+			visits.visit_count, visits.visit_time, visits.url_id, visits.typed_count,
+			visits.transition, visits.referrer_visit_id, visits.duration
+		FROM ` + from
+	if len(conds) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(conds, " AND ")
+	}
+	query += "\n\t\tORDER BY " + order + ", visits.browser, visits.profile_dir, visits.visit_id DESC"
+	if limit > 0 {
+		query += fmt.Sprintf("\n\t\tLIMIT %d", limit)
+	}
+
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archive: %w", err)
+	}
+	defer rows.Close()
+
+	var history []types.VisitEntry
+	for rows.Next() {
+		var e types.VisitEntry
+		var visitTime, duration int64
+		var transition string
+
+		err := rows.Scan(&e.Browser, &e.Profile, &e.ProfileDir, &e.VisitID, &e.URL, &e.Title, &e.VisitCount,
+			&visitTime, &e.URLID, &e.TypedCount, &transition, &e.ReferrerVisitID, &duration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan archive row: %w", err)
+		}
+
+		e.VisitTime = time.UnixMicro(visitTime).UTC()
+		e.Transition = types.Transition(transition)
+		e.Duration = time.Duration(duration) * time.Microsecond
+		history = append(history, e)
+	}
+	return history, rows.Err()
+}
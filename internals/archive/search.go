@@ -0,0 +1,134 @@
+package archive
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+// The search index is an FTS5 table whose rowids match the visits table. FTS5
+// is only compiled into go-sqlite3 with the sqlite_fts5 build tag; without it
+// Search falls back to LIKE matching, which is slower and ranks more crudely.
+const ftsSchema = `
+CREATE VIRTUAL TABLE IF NOT EXISTS visits_fts USING fts5 (title, url, domain, tokenize = 'unicode61');
+`
+
+// initSearch creates the search index if FTS5 is available and indexes any
+// visits archived while it was not. It reports whether FTS5 is available.
+func (a *Archive) initSearch() (bool, error) {
+	if _, err := a.db.Exec(ftsSchema); err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create search index: %w", err)
+	}
+
+	rows, err := a.db.Query(`
+		SELECT rowid, title, url FROM visits
+		WHERE rowid NOT IN (SELECT rowid FROM visits_fts)`)
+	if err != nil {
+		return false, fmt.Errorf("failed to read unindexed visits: %w", err)
+	}
+	type pending struct {
+		rowid      int64
+		title, url string
+	}
+	var missing []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.rowid, &p.title, &p.url); err != nil {
+			rows.Close()
+			return false, fmt.Errorf("failed to scan unindexed visit: %w", err)
+		}
+		missing = append(missing, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to read unindexed visits: %w", err)
+	}
+
+	for _, p := range missing {
+		if err := indexVisit(a.db, p.rowid, p.title, p.url); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// execer is the part of *sql.DB and *sql.Tx that indexVisit needs.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// indexVisit adds or replaces a visit in the search index.
+func indexVisit(db execer, rowid int64, title, rawURL string) error {
+	if _, err := db.Exec(`DELETE FROM visits_fts WHERE rowid = ?`, rowid); err != nil {
+		return fmt.Errorf("failed to update search index: %w", err)
+	}
+	_, err := db.Exec(`INSERT INTO visits_fts (rowid, title, url, domain) VALUES (?, ?, ?, ?)`,
+		rowid, title, rawURL, stats.Domain(rawURL))
+	if err != nil {
+		return fmt.Errorf("failed to update search index: %w", err)
+	}
+	return nil
+}
+
+// searchTerms splits user input into terms, dropping empty ones.
+func searchTerms(text string) []string {
+	return strings.Fields(strings.ToLower(text))
+}
+
+// ftsMatch turns terms into an FTS5 query where every term must match as a
+// prefix. Terms are quoted so FTS5 operators in user input are taken literally.
+func ftsMatch(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(t, `"`, `""`) + `"*`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// FullText reports whether Search uses the FTS5 index. Without it, searches
+// fall back to substring matching.
+func (a *Archive) FullText() bool {
+	return a.fts
+}
+
+// Search returns the archived entries matching every term of text in their
+// title, URL or domain, best match first. q narrows the results like in Entries.
+func (a *Archive) Search(text string, q Query) ([]types.VisitEntry, error) {
+	terms := searchTerms(text)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty search query")
+	}
+
+	conds, args := q.conditions()
+
+	var from, order string
+	if a.fts {
+		// Title matches weigh the most, then domain, then the rest of the URL.
+		from = "visits JOIN visits_fts ON visits_fts.rowid = visits.rowid"
+		conds = append([]string{"visits_fts MATCH ?"}, conds...)
+		args = append([]any{ftsMatch(terms)}, args...)
+		order = "bm25(visits_fts, 10.0, 1.0, 5.0), visits.visit_time DESC"
+	} else {
+		from = "visits"
+		var titleHits []string
+		var titleArgs []any
+		for _, t := range terms {
+			pattern := "%" + parse.EscapeLike(t) + "%"
+			conds = append(conds, `(LOWER(visits.title) LIKE ? ESCAPE '\' OR LOWER(visits.url) LIKE ? ESCAPE '\')`)
+			args = append(args, pattern, pattern)
+			titleHits = append(titleHits, `(LOWER(visits.title) LIKE ? ESCAPE '\')`)
+			titleArgs = append(titleArgs, pattern)
+		}
+		order = "(" + strings.Join(titleHits, " + ") + ") DESC, visits.visit_time DESC"
+		args = append(args, titleArgs...)
+	}
+
+	return a.queryEntries(from, conds, args, order, q.Limit)
+}
@@ -0,0 +1,95 @@
+package archive
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+)
+
+// SyncResult reports what syncing one browser profile added to the archive.
+type SyncResult struct {
+	Browser string
+	Profile string
+	Added   int
+	Err     error
+}
+
+// HighWaterMark returns the time of the newest visit synced from the browser
+// profile in profileDir, or the zero time if it was never synced.
+func (a *Archive) HighWaterMark(browser, profileDir string) (time.Time, error) {
+	var last int64
+	err := a.db.QueryRow(`SELECT last_visit_time FROM sync_state WHERE browser = ? AND profile_dir = ?`,
+		browser, profileDir).Scan(&last)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read sync state: %w", err)
+	}
+	return time.UnixMicro(last).UTC(), nil
+}
+
+// setHighWaterMark records the newest visit synced from the browser profile
+// in profileDir. The mark never moves backwards.
+func (a *Archive) setHighWaterMark(browser, profileDir string, last time.Time) error {
+	_, err := a.db.Exec(`
+		INSERT INTO sync_state (browser, profile_dir, last_visit_time, synced_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (browser, profile_dir) DO UPDATE SET
+			last_visit_time = MAX(last_visit_time, excluded.last_visit_time),
+			synced_at = excluded.synced_at`,
+		browser, profileDir, last.UnixMicro(), time.Now().UnixMicro())
+	if err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	return nil
+}
+
+// Sync pulls the visits newer than each profile's high-water mark from every
+// profile of the given sources into the archive. A failing profile is reported
+// in its SyncResult and does not stop the others.
+func (a *Archive) Sync(sources []parse.HistorySource) []SyncResult {
+	var results []SyncResult
+	for _, src := range sources {
+		profiles, err := src.Profiles()
+		if err != nil {
+			results = append(results, SyncResult{Browser: src.Name(), Err: err})
+			continue
+		}
+		for _, profile := range profiles {
+			added, err := a.syncProfile(src, profile)
+			results = append(results, SyncResult{
+				Browser: src.Name(),
+				Profile: profile.Name,
+				Added:   added,
+				Err:     err,
+			})
+		}
+	}
+	return results
+}
+
+func (a *Archive) syncProfile(src parse.HistorySource, profile parse.Profile) (int, error) {
+	since, err := a.HighWaterMark(src.Name(), profile.Dir)
+	if err != nil {
+		return 0, err
+	}
+
+	// Since is inclusive, so visits sharing the mark's timestamp are read again
+	// and dropped by Ingest rather than missed.
+	entries, err := parse.Parse(src, parse.Options{Since: since}, profile)
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	added, err := a.Ingest(entries)
+	if err != nil {
+		return 0, err
+	}
+	// Entries come back most recent first.
+	return added, a.setHighWaterMark(src.Name(), profile.Dir, entries[0].VisitTime)
+}
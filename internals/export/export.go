@@ -0,0 +1,175 @@
+// Package export writes history entries in formats other tools can read.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+// Format is an export file format.
+type Format string
+
+const (
+	// JSON writes a single indented JSON array.
+	JSON Format = "json"
+	// NDJSON writes one compact JSON object per line.
+	NDJSON Format = "ndjson"
+	// CSV writes RFC 4180 CSV with a header row.
+	CSV Format = "csv"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{JSON, NDJSON, CSV}
+
+// Columns are the CSV columns, in the order of VisitEntry's JSON fields.
+var Columns = []string{
+	"url", "title", "visit_count", "visit_time", "browser", "profile",
+	"visit_id", "url_id", "typed_count", "transition", "referrer_visit_id", "duration",
+}
+
+// Encoder writes entries one at a time, so exports do not have to be held in
+// memory. Close must be called to finish the output.
+type Encoder interface {
+	Encode(entry types.VisitEntry) error
+	Close() error
+}
+
+// NewEncoder returns an Encoder writing format to w.
+func NewEncoder(w io.Writer, format Format) (Encoder, error) {
+	switch format {
+	case JSON:
+		return &jsonEncoder{w: w}, nil
+	case NDJSON:
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}, nil
+	case CSV:
+		cw := csv.NewWriter(w)
+		cw.UseCRLF = true // RFC 4180 line endings
+		return &csvEncoder{w: cw}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// Write writes entries to w in format.
+func Write(w io.Writer, entries []types.VisitEntry, format Format) error {
+	enc, err := NewEncoder(w, format)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
+
+// jsonEncoder streams an indented JSON array.
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) Encode(entry types.VisitEntry) error {
+	data, err := json.MarshalIndent(entry, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode entry: %w", err)
+	}
+
+	sep := ",\n  "
+	if e.count == 0 {
+		sep = "[\n  "
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+func (e *jsonEncoder) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := io.WriteString(e.w, end); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// ndjsonEncoder writes one JSON object per line.
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(entry types.VisitEntry) error {
+	if err := e.enc.Encode(entry); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+func (e *ndjsonEncoder) Close() error { return nil }
+
+// csvEncoder writes the header before the first row. Values are formatted as
+// in the JSON export: times in RFC 3339 and durations in nanoseconds.
+type csvEncoder struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.header {
+		return nil
+	}
+	e.header = true
+	if err := e.w.Write(Columns); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+func (e *csvEncoder) Encode(entry types.VisitEntry) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	err := e.w.Write([]string{
+		entry.URL,
+		entry.Title,
+		strconv.Itoa(entry.VisitCount),
+		entry.VisitTime.Format(time.RFC3339Nano),
+		entry.Browser,
+		entry.Profile,
+		strconv.FormatInt(entry.VisitID, 10),
+		strconv.FormatInt(entry.URLID, 10),
+		strconv.Itoa(entry.TypedCount),
+		string(entry.Transition),
+		strconv.FormatInt(entry.ReferrerVisitID, 10),
+		strconv.FormatInt(int64(entry.Duration), 10),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+func (e *csvEncoder) Close() error {
+	// An empty export still gets its header
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
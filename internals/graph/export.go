@@ -0,0 +1,137 @@
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// nodes returns the distinct nodes of edges in order of first appearance.
+func nodes(edges []Edge) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, e := range edges {
+		for _, name := range []string{e.From, e.To} {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// dotQuote quotes s as a DOT string.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// WriteDOT writes edges as a Graphviz digraph. Heavier edges are drawn thicker.
+func WriteDOT(w io.Writer, edges []Edge) error {
+	maxWeight := 1
+	for _, e := range edges {
+		maxWeight = max(maxWeight, e.Weight)
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph histograph {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	for _, name := range nodes(edges) {
+		fmt.Fprintf(&b, "  %s;\n", dotQuote(name))
+	}
+	for _, e := range edges {
+		width := 1 + 4*float64(e.Weight)/float64(maxWeight)
+		fmt.Fprintf(&b, "  %s -> %s [weight=%d, label=\"%d\", penwidth=%.1f];\n",
+			dotQuote(e.From), dotQuote(e.To), e.Weight, e.Weight, width)
+	}
+	b.WriteString("}\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write DOT graph: %w", err)
+	}
+	return nil
+}
+
+// GraphML document structure, see http://graphml.graphdrawing.org/
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// WriteGraphML writes edges as a directed GraphML graph with a label on every
+// node and a weight on every edge.
+func WriteGraphML(w io.Writer, edges []Edge) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", Name: "label", Type: "string"},
+			{ID: "weight", For: "edge", Name: "weight", Type: "int"},
+		},
+		Graph: graphMLGraph{ID: "histograph", EdgeDefault: "directed"},
+	}
+
+	ids := make(map[string]string)
+	for i, name := range nodes(edges) {
+		ids[name] = "n" + strconv.Itoa(i)
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID:   ids[name],
+			Data: []graphMLData{{Key: "label", Value: name}},
+		})
+	}
+	for _, e := range edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: ids[e.From],
+			Target: ids[e.To],
+			Data:   []graphMLData{{Key: "weight", Value: strconv.Itoa(e.Weight)}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write GraphML graph: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write GraphML graph: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write GraphML graph: %w", err)
+	}
+	return nil
+}
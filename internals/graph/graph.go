@@ -0,0 +1,132 @@
+// Package graph builds the navigation graph encoded in visit referrers: which
+// pages and domains lead to which.
+package graph
+
+import (
+	"sort"
+
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+// maxChain bounds referrer chains, in case a browser database contains a cycle.
+const maxChain = 100
+
+// Edge is a weighted link: Weight visits to To came from From.
+type Edge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Weight int    `json:"weight"`
+}
+
+// Level selects what the nodes of a graph are.
+type Level string
+
+const (
+	Pages   Level = "page"   // nodes are URLs
+	Domains Level = "domain" // nodes are domains
+)
+
+// Graph indexes visits by their referrers.
+type Graph struct {
+	visits   map[types.VisitKey]types.VisitEntry
+	children map[types.VisitKey][]types.VisitEntry
+	pages    []Edge
+	domains  []Edge
+}
+
+// Build indexes entries. Referrers that are not among entries, for example
+// because they were cut off by a limit, are ignored.
+func Build(entries []types.VisitEntry) *Graph {
+	g := &Graph{
+		visits:   make(map[types.VisitKey]types.VisitEntry, len(entries)),
+		children: make(map[types.VisitKey][]types.VisitEntry),
+	}
+	for _, entry := range entries {
+		g.visits[entry.Key()] = entry
+	}
+
+	pages := make(map[[2]string]int)
+	domains := make(map[[2]string]int)
+	for _, entry := range entries {
+		from, ok := g.Referrer(entry)
+		if !ok {
+			continue
+		}
+		g.children[from.Key()] = append(g.children[from.Key()], entry)
+
+		pages[[2]string{from.URL, entry.URL}]++
+		// Links within a domain are not domain-to-domain navigation
+		if fromDomain, toDomain := stats.Domain(from.URL), stats.Domain(entry.URL); fromDomain != toDomain {
+			domains[[2]string{fromDomain, toDomain}]++
+		}
+	}
+
+	for _, children := range g.children {
+		sort.SliceStable(children, func(i, j int) bool {
+			return children[i].VisitTime.Before(children[j].VisitTime)
+		})
+	}
+	g.pages = sortedEdges(pages)
+	g.domains = sortedEdges(domains)
+	return g
+}
+
+// Referrer returns the visit entry came from, if it is known.
+func (g *Graph) Referrer(entry types.VisitEntry) (types.VisitEntry, bool) {
+	if entry.ReferrerVisitID == 0 {
+		return types.VisitEntry{}, false
+	}
+	from, ok := g.visits[entry.ReferrerKey()]
+	return from, ok
+}
+
+// Edges returns the edges at level, heaviest first.
+func (g *Graph) Edges(level Level) []Edge {
+	if level == Domains {
+		return g.domains
+	}
+	return g.pages
+}
+
+// Chain returns the visits that led to entry by following referrers, starting
+// with the first visit of the chain and ending with entry itself.
+func (g *Graph) Chain(entry types.VisitEntry) []types.VisitEntry {
+	chain := []types.VisitEntry{entry}
+	seen := map[types.VisitKey]bool{entry.Key(): true}
+	for len(chain) < maxChain {
+		from, ok := g.Referrer(chain[len(chain)-1])
+		if !ok || seen[from.Key()] {
+			break
+		}
+		seen[from.Key()] = true
+		chain = append(chain, from)
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// Next returns the visits that came from entry, oldest first.
+func (g *Graph) Next(entry types.VisitEntry) []types.VisitEntry {
+	return g.children[entry.Key()]
+}
+
+func sortedEdges(weights map[[2]string]int) []Edge {
+	edges := make([]Edge, 0, len(weights))
+	for pair, weight := range weights {
+		edges = append(edges, Edge{From: pair[0], To: pair[1], Weight: weight})
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Weight != edges[j].Weight {
+			return edges[i].Weight > edges[j].Weight
+		}
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
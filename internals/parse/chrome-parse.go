@@ -13,61 +13,178 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-// Converts Chrome's Webkit timestamp to Unix time
-func chromeTimeToUnix(microseconds int64) time.Time {
-	const offset = 11644473600
-	seconds := microseconds/1000000 - offset
-	return time.Unix(seconds, 0)
+// webkitEpochOffset is the number of microseconds between 1601-01-01 UTC, the epoch
+// of Chrome's Webkit timestamps, and the Unix epoch.
+const webkitEpochOffset = 11644473600 * 1000000
+
+// ChromeTimeToUnix converts Chrome's Webkit timestamp (microseconds since
+// 1601-01-01 UTC) to a UTC time, keeping microsecond precision.
+func ChromeTimeToUnix(microseconds int64) time.Time {
+	return time.UnixMicro(microseconds - webkitEpochOffset).UTC()
 }
 
-// getChromeHistoryPath returns the path to the Chrome history file for the current OS.
-func GetChromeHistoryPath() (string, error) {
-	// Allow override via environment variable
-	if envPath := os.Getenv("CHROME_HISTORY_PATH"); envPath != "" {
-		return envPath, nil
+// unixToChromeTime converts a time to Chrome's Webkit timestamp
+func unixToChromeTime(t time.Time) int64 {
+	return t.UnixMicro() + webkitEpochOffset
+}
+
+// chromiumSource reads the History database shared by every Chromium-based browser.
+// The browsers only differ in where they keep their user data directory.
+type chromiumSource struct {
+	name   string
+	desc   string
+	envVar string
+	// userDataDirs maps runtime.GOOS to the user data directory, relative to the home directory.
+	userDataDirs map[string]string
+	// profileDir is the profile directory inside the user data directory. Opera keeps
+	// its History file directly in the user data directory, so it leaves this empty.
+	profileDir string
+}
+
+var chrome = chromiumSource{
+	name:   "Chrome",
+	desc:   "Google Chrome history file",
+	envVar: "CHROME_HISTORY_PATH",
+	userDataDirs: map[string]string{
+		"linux":   filepath.Join(".config", "google-chrome"),
+		"darwin":  filepath.Join("Library", "Application Support", "Google", "Chrome"),
+		"windows": filepath.Join("AppData", "Local", "Google", "Chrome", "User Data"),
+	},
+	profileDir: "Default",
+}
+
+var chromiumBrowsers = []chromiumSource{
+	{
+		name:   "Chromium",
+		desc:   "Chromium history file",
+		envVar: "CHROMIUM_HISTORY_PATH",
+		userDataDirs: map[string]string{
+			"linux":   filepath.Join(".config", "chromium"),
+			"darwin":  filepath.Join("Library", "Application Support", "Chromium"),
+			"windows": filepath.Join("AppData", "Local", "Chromium", "User Data"),
+		},
+		profileDir: "Default",
+	},
+	{
+		name:   "Brave",
+		desc:   "Brave Browser history file",
+		envVar: "BRAVE_HISTORY_PATH",
+		userDataDirs: map[string]string{
+			"linux":   filepath.Join(".config", "BraveSoftware", "Brave-Browser"),
+			"darwin":  filepath.Join("Library", "Application Support", "BraveSoftware", "Brave-Browser"),
+			"windows": filepath.Join("AppData", "Local", "BraveSoftware", "Brave-Browser", "User Data"),
+		},
+		profileDir: "Default",
+	},
+	{
+		name:   "Edge",
+		desc:   "Microsoft Edge history file",
+		envVar: "EDGE_HISTORY_PATH",
+		userDataDirs: map[string]string{
+			"linux":   filepath.Join(".config", "microsoft-edge"),
+			"darwin":  filepath.Join("Library", "Application Support", "Microsoft Edge"),
+			"windows": filepath.Join("AppData", "Local", "Microsoft", "Edge", "User Data"),
+		},
+		profileDir: "Default",
+	},
+	{
+		name:   "Vivaldi",
+		desc:   "Vivaldi history file",
+		envVar: "VIVALDI_HISTORY_PATH",
+		userDataDirs: map[string]string{
+			"linux":   filepath.Join(".config", "vivaldi"),
+			"darwin":  filepath.Join("Library", "Application Support", "Vivaldi"),
+			"windows": filepath.Join("AppData", "Local", "Vivaldi", "User Data"),
+		},
+		profileDir: "Default",
+	},
+	{
+		name:   "Opera",
+		desc:   "Opera history file",
+		envVar: "OPERA_HISTORY_PATH",
+		userDataDirs: map[string]string{
+			"linux":   filepath.Join(".config", "opera"),
+			"darwin":  filepath.Join("Library", "Application Support", "com.operasoftware.Opera"),
+			"windows": filepath.Join("AppData", "Roaming", "Opera Software", "Opera Stable"),
+		},
+	},
+}
+
+func init() {
+	Register(chrome)
+	for _, b := range chromiumBrowsers {
+		Register(b)
 	}
+}
 
+// GetChromeHistoryPath returns the path to the Chrome history file for the current OS.
+func GetChromeHistoryPath() (string, error) {
+	return chrome.historyPath()
+}
+
+// userDataDir returns the browser's user data directory for the current OS.
+func (s chromiumSource) userDataDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get user home directory: %w", err)
 	}
 
-	switch runtime.GOOS {
-	case "linux":
-		return filepath.Join(home, ".config", "google-chrome", "Default", "History"), nil
-	case "darwin":
-		return filepath.Join(home, "Library", "Application Support", "Google", "Chrome", "Default", "History"), nil
-	case "windows":
-		return filepath.Join(home, "AppData", "Local", "Google", "Chrome", "User Data", "Default", "History"), nil
-	default:
+	dir, ok := s.userDataDirs[runtime.GOOS]
+	if !ok {
 		return "", fmt.Errorf("unsupported OS: %s", runtime.GOOS)
 	}
+
+	return filepath.Join(home, dir), nil
 }
 
-// ParseChromeHistory connects to Chrome's history database and returns a slice of VisitEntry
-func ParseChromeHistory() ([]types.VisitEntry, error) {
-	fmt.Println("Parsing Chrome's History")
+// historyPath returns the path to the default profile's History file for the current OS.
+func (s chromiumSource) historyPath() (string, error) {
+	// Allow override via environment variable
+	if envPath := os.Getenv(s.envVar); envPath != "" {
+		return envPath, nil
+	}
 
-	historyPath, err := GetChromeHistoryPath()
+	dir, err := s.userDataDir()
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+
+	return filepath.Join(dir, s.profileDir, "History"), nil
+}
+
+func (s chromiumSource) Name() string        { return s.name }
+func (s chromiumSource) Description() string { return s.desc }
+
+func (s chromiumSource) Detect() bool { return detect(s) }
+
+func (s chromiumSource) Profiles() ([]Profile, error) {
+	if envPath := os.Getenv(s.envVar); envPath != "" {
+		return []Profile{envProfile(envPath)}, nil
 	}
 
-	db, err := sql.Open("sqlite3", historyPath)
+	dir, err := s.userDataDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to open Chrome history database: %w", err)
+		return nil, err
 	}
-	defer db.Close()
-
-	rows, err := db.Query(`
-	  SELECT urls.url, urls.title, urls.visit_count, visits.visit_time
-        FROM urls
-        JOIN visits ON urls.id = visits.url
-        ORDER BY visits.visit_time DESC
-        LIMIT 20;
-	`)
+	return chromeProfiles(dir, s.profileDir)
+}
+
+func (s chromiumSource) Open(profile Profile) (*Snapshot, error) {
+	return openSnapshot(profile.Path, s.name)
+}
+
+func (s chromiumSource) Entries(db *sql.DB, opts Options) ([]types.VisitEntry, error) {
+	query, args := opts.query(`
+		SELECT urls.url, urls.title, urls.visit_count, visits.visit_time,
+			visits.id, urls.id, urls.typed_count, visits.transition,
+			COALESCE(visits.from_visit, 0), visits.visit_duration
+		FROM urls
+		JOIN visits ON urls.id = visits.url`,
+		"visits.visit_time", "urls.url", unixToChromeTime)
+
+	rows, err := db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query Chrome history: %w", err)
+		return nil, fmt.Errorf("failed to query %s history: %w", s.name, err)
 	}
 	defer rows.Close()
 
@@ -75,24 +192,59 @@ func ParseChromeHistory() ([]types.VisitEntry, error) {
 
 	for rows.Next() {
 		var url string
-		var title string
+		var title sql.NullString
 		var visitCount int
 		var visitTime int64
+		var entry types.VisitEntry
+		var transition int64
+		var duration int64
 
-		err = rows.Scan(&url, &title, &visitCount, &visitTime)
+		err = rows.Scan(&url, &title, &visitCount, &visitTime,
+			&entry.VisitID, &entry.URLID, &entry.TypedCount, &transition,
+			&entry.ReferrerVisitID, &duration)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan Chrome history row: %w", err)
+			return nil, fmt.Errorf("failed to scan %s history row: %w", s.name, err)
 		}
 
-		convertedTime := chromeTimeToUnix(visitTime)
+		entry.URL = url
+		entry.Title = title.String
+		entry.VisitCount = visitCount
+		entry.VisitTime = ChromeTimeToUnix(visitTime)
+		entry.Transition = chromeTransition(transition)
+		entry.Duration = time.Duration(duration) * time.Microsecond
+
+		history = append(history, entry)
+	}
+
+	return history, rows.Err()
+}
 
-		history = append(history, types.VisitEntry{
-			URL:        url,
-			Title:      title,
-			VisitCount: visitCount,
-			VisitTime:  convertedTime,
-		})
+// chromeTransition maps the core type in the low byte of visits.transition.
+// See ui::PageTransition in Chromium.
+func chromeTransition(transition int64) types.Transition {
+	switch transition & 0xFF {
+	case 0:
+		return types.TransitionLink
+	case 1:
+		return types.TransitionTyped
+	case 2:
+		return types.TransitionBookmark
+	case 3, 4:
+		return types.TransitionSubframe
+	case 5, 6:
+		return types.TransitionGenerated
+	case 7:
+		return types.TransitionFormSubmit
+	case 8:
+		return types.TransitionReload
+	case 9, 10:
+		return types.TransitionKeyword
+	default:
+		return types.TransitionUnknown
 	}
+}
 
-	return history, nil
+// ParseChromeHistory connects to Chrome's history database and returns a slice of VisitEntry
+func ParseChromeHistory(opts Options) ([]types.VisitEntry, error) {
+	return Parse(chrome, opts)
 }
@@ -12,73 +12,145 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-// Convert Firefox microsecond timestamp to time.Time
-func firefoxTimeToUnix(microseconds int64) time.Time {
-	return time.UnixMicro(microseconds)
+// FirefoxTimeToUnix converts Firefox's PRTime (microseconds since the Unix epoch)
+// to a UTC time.
+func FirefoxTimeToUnix(microseconds int64) time.Time {
+	return time.UnixMicro(microseconds).UTC()
 }
 
-// Get the path to the first available Firefox profile
-func GetFirefoxHistoryPath() (string, error) {
-	// Allow override via environment variable
-	if envPath := os.Getenv("FIREFOX_HISTORY_PATH"); envPath != "" {
-		return envPath, nil
+// geckoSource reads the places.sqlite database shared by Firefox and its forks.
+// The browsers only differ in where they keep their profiles.
+type geckoSource struct {
+	name   string
+	desc   string
+	envVar string
+	// profileRoots maps runtime.GOOS to the directory holding profiles.ini,
+	// relative to the home directory.
+	profileRoots map[string]string
+}
+
+var firefox = geckoSource{
+	name:   "Firefox",
+	desc:   "Mozilla Firefox history file",
+	envVar: "FIREFOX_HISTORY_PATH",
+	profileRoots: map[string]string{
+		"linux":   filepath.Join(".mozilla", "firefox"),
+		"darwin":  filepath.Join("Library", "Application Support", "Firefox"),
+		"windows": filepath.Join("AppData", "Roaming", "Mozilla", "Firefox"),
+	},
+}
+
+var geckoBrowsers = []geckoSource{
+	{
+		name:   "LibreWolf",
+		desc:   "LibreWolf history file",
+		envVar: "LIBREWOLF_HISTORY_PATH",
+		profileRoots: map[string]string{
+			"linux":   ".librewolf",
+			"darwin":  filepath.Join("Library", "Application Support", "librewolf"),
+			"windows": filepath.Join("AppData", "Roaming", "librewolf"),
+		},
+	},
+	{
+		name:   "Waterfox",
+		desc:   "Waterfox history file",
+		envVar: "WATERFOX_HISTORY_PATH",
+		profileRoots: map[string]string{
+			"linux":   ".waterfox",
+			"darwin":  filepath.Join("Library", "Application Support", "Waterfox"),
+			"windows": filepath.Join("AppData", "Roaming", "Waterfox"),
+		},
+	},
+	{
+		name:   "Zen",
+		desc:   "Zen Browser history file",
+		envVar: "ZEN_HISTORY_PATH",
+		profileRoots: map[string]string{
+			"linux":   ".zen",
+			"darwin":  filepath.Join("Library", "Application Support", "zen"),
+			"windows": filepath.Join("AppData", "Roaming", "zen"),
+		},
+	},
+	{
+		name:   "Floorp",
+		desc:   "Floorp history file",
+		envVar: "FLOORP_HISTORY_PATH",
+		profileRoots: map[string]string{
+			"linux":   ".floorp",
+			"darwin":  filepath.Join("Library", "Application Support", "Floorp"),
+			"windows": filepath.Join("AppData", "Roaming", "Floorp"),
+		},
+	},
+}
+
+func init() {
+	Register(firefox)
+	for _, b := range geckoBrowsers {
+		Register(b)
 	}
+}
 
-	homeDir, err := os.UserHomeDir()
+// Get the path to the default Firefox profile's history
+func GetFirefoxHistoryPath() (string, error) {
+	profiles, err := firefox.Profiles()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home dir: %w", err)
+		return "", err
 	}
 
-	var profilesDir string
-	switch runtime.GOOS {
-	case "linux":
-		profilesDir = filepath.Join(homeDir, ".mozilla", "firefox")
-	case "darwin":
-		profilesDir = filepath.Join(homeDir, "Library", "Application Support", "Firefox", "Profiles")
-	case "windows":
-		profilesDir = filepath.Join(homeDir, "AppData", "Roaming", "Mozilla", "Firefox", "Profiles")
-	default:
-		return "", fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+	profile, err := DefaultProfile(profiles)
+	if err != nil {
+		return "", fmt.Errorf("could not find a Firefox profile: %w", err)
 	}
+	return profile.Path, nil
+}
 
-	dirs, err := os.ReadDir(profilesDir)
+// profileRoot returns the directory holding the browser's profiles.ini for the current OS.
+func (s geckoSource) profileRoot() (string, error) {
+	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to read firefox profile dir: %w", err)
+		return "", fmt.Errorf("failed to get home dir: %w", err)
 	}
 
-	for _, d := range dirs {
-		if d.IsDir() && (filepath.Ext(d.Name()) == ".default-release" || filepath.Ext(d.Name()) == ".default") {
-			return filepath.Join(profilesDir, d.Name(), "places.sqlite"), nil
-		}
+	dir, ok := s.profileRoots[runtime.GOOS]
+	if !ok {
+		return "", fmt.Errorf("unsupported OS: %s", runtime.GOOS)
 	}
-
-	return "", fmt.Errorf("could not find Firefox default(-release) profile")
+	return filepath.Join(homeDir, dir), nil
 }
 
-// ParseFirefoxHistory connects to Firefox's history database and returns recent visits
-func ParseFirefoxHistory() ([]types.VisitEntry, error) {
-	fmt.Println("Parsing Firefox History")
+func (s geckoSource) Name() string        { return s.name }
+func (s geckoSource) Description() string { return s.desc }
 
-	historyPath, err := GetFirefoxHistoryPath()
-	if err != nil {
-		return nil, err
+func (s geckoSource) Detect() bool { return detect(s) }
+
+func (s geckoSource) Profiles() ([]Profile, error) {
+	// Allow override via environment variable
+	if envPath := os.Getenv(s.envVar); envPath != "" {
+		return []Profile{envProfile(envPath)}, nil
 	}
 
-	db, err := sql.Open("sqlite3", historyPath)
+	root, err := s.profileRoot()
 	if err != nil {
-		return nil, fmt.Errorf("failed to open Firefox history database: %w", err)
+		return nil, err
 	}
-	defer db.Close()
+	return geckoProfiles(root)
+}
 
-	rows, err := db.Query(`
-		SELECT p.url, p.title, p.visit_count, v.visit_date
+func (s geckoSource) Open(profile Profile) (*Snapshot, error) {
+	return openSnapshot(profile.Path, s.name)
+}
+
+func (s geckoSource) Entries(db *sql.DB, opts Options) ([]types.VisitEntry, error) {
+	query, args := opts.query(`
+		SELECT p.url, p.title, p.visit_count, v.visit_date,
+			v.id, p.id, p.typed, COALESCE(v.visit_type, 0), COALESCE(v.from_visit, 0)
 		FROM moz_places p
-		JOIN moz_historyvisits v ON p.id = v.place_id
-		ORDER BY v.visit_date DESC
-		LIMIT 20;
-	`)
+		JOIN moz_historyvisits v ON p.id = v.place_id`,
+		"v.visit_date", "p.url", time.Time.UnixMicro)
+
+	rows, err := db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query Firefox history: %w", err)
+		return nil, fmt.Errorf("failed to query %s history: %w", s.name, err)
 	}
 	defer rows.Close()
 
@@ -86,24 +158,54 @@ func ParseFirefoxHistory() ([]types.VisitEntry, error) {
 
 	for rows.Next() {
 		var url string
-		var title string
+		var title sql.NullString
 		var visitCount int
 		var visitTime int64
+		var entry types.VisitEntry
+		var visitType int64
 
-		err = rows.Scan(&url, &title, &visitCount, &visitTime)
+		// moz_places only records whether a URL was ever typed, so TypedCount is 0 or 1.
+		err = rows.Scan(&url, &title, &visitCount, &visitTime,
+			&entry.VisitID, &entry.URLID, &entry.TypedCount, &visitType, &entry.ReferrerVisitID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan Firefox history row: %w", err)
+			return nil, fmt.Errorf("failed to scan %s history row: %w", s.name, err)
 		}
 
-		convertedTime := firefoxTimeToUnix(visitTime)
+		entry.URL = url
+		entry.Title = title.String
+		entry.VisitCount = visitCount
+		entry.VisitTime = FirefoxTimeToUnix(visitTime)
+		entry.Transition = firefoxTransition(visitType)
 
-		history = append(history, types.VisitEntry{
-			URL:        url,
-			Title:      title,
-			VisitCount: visitCount,
-			VisitTime:  convertedTime,
-		})
+		history = append(history, entry)
 	}
 
-	return history, nil
+	return history, rows.Err()
+}
+
+// firefoxTransition maps moz_historyvisits.visit_type. See nsINavHistoryService.
+func firefoxTransition(visitType int64) types.Transition {
+	switch visitType {
+	case 1, 8:
+		return types.TransitionLink
+	case 2:
+		return types.TransitionTyped
+	case 3:
+		return types.TransitionBookmark
+	case 4:
+		return types.TransitionSubframe
+	case 5, 6:
+		return types.TransitionRedirect
+	case 7:
+		return types.TransitionDownload
+	case 9:
+		return types.TransitionReload
+	default:
+		return types.TransitionUnknown
+	}
+}
+
+// ParseFirefoxHistory connects to Firefox's history database and returns recent visits
+func ParseFirefoxHistory(opts Options) ([]types.VisitEntry, error) {
+	return Parse(firefox, opts)
 }
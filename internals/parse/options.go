@@ -0,0 +1,71 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Options narrows down which visits are read from a history database. The zero
+// value reads every visit.
+type Options struct {
+	// Limit caps the number of visits read per profile. Zero means no limit.
+	Limit int
+	// Since drops visits before this time. Zero means no lower bound.
+	Since time.Time
+	// Until drops visits at or after this time. Zero means no upper bound.
+	Until time.Time
+	// Domain keeps only visits to this host or its subdomains, e.g. "github.com".
+	Domain string
+}
+
+// query appends the WHERE, ORDER BY and LIMIT clauses for o to a SELECT statement.
+// timeCol and urlCol name the visit time and URL columns, and toDB converts a
+// time into the unit timeCol is stored in.
+func (o Options) query(base, timeCol, urlCol string, toDB func(time.Time) int64) (string, []any) {
+	conds, args := o.Conditions(timeCol, urlCol, toDB)
+
+	q := base
+	if len(conds) > 0 {
+		q += "\n\t\tWHERE " + strings.Join(conds, " AND ")
+	}
+	q += "\n\t\tORDER BY " + timeCol + " DESC"
+	if o.Limit > 0 {
+		q += fmt.Sprintf("\n\t\tLIMIT %d", o.Limit)
+	}
+	return q, args
+}
+
+// Conditions returns the SQL conditions, to be joined with AND, and their
+// arguments for the time window and domain filter of o. Limit is left to the caller.
+func (o Options) Conditions(timeCol, urlCol string, toDB func(time.Time) int64) ([]string, []any) {
+	var conds []string
+	var args []any
+
+	if !o.Since.IsZero() {
+		conds = append(conds, timeCol+" >= ?")
+		args = append(args, toDB(o.Since))
+	}
+	if !o.Until.IsZero() {
+		conds = append(conds, timeCol+" < ?")
+		args = append(args, toDB(o.Until))
+	}
+	if domain := strings.ToLower(strings.TrimSpace(o.Domain)); domain != "" {
+		// Match the host exactly or as a subdomain, with or without a port. The
+		// host runs from the scheme to the first slash, which browsers always
+		// store, so a domain in the path or query string does not match.
+		rest := "substr(LOWER(" + urlCol + "), instr(" + urlCol + ", '://') + 3)"
+		host := "substr(" + rest + ", 1, instr(" + rest + " || '/', '/') - 1)"
+		conds = append(conds, `'.' || `+host+` || ':' LIKE ? ESCAPE '\'`)
+		args = append(args, "%."+EscapeLike(domain)+":%")
+	}
+	return conds, args
+}
+
+// EscapeLike escapes the LIKE wildcards in s, for patterns used with
+// ESCAPE '\'.
+func EscapeLike(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "%", `\%`)
+	return strings.ReplaceAll(s, "_", `\_`)
+}
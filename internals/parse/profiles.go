@@ -0,0 +1,229 @@
+package parse
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Profile is a single browser profile with its own history database.
+type Profile struct {
+	// Name is the display name the user gave the profile, e.g. "Work".
+	Name string
+	// Dir is the profile's directory name, e.g. "Profile 1" or "abcd1234.default-release".
+	Dir string
+	// Path is the location of the profile's history database.
+	Path string
+	// Default marks the profile the browser opens by default.
+	Default bool
+}
+
+// DefaultProfile returns the default profile from profiles, or the first one if
+// none is marked as default.
+func DefaultProfile(profiles []Profile) (Profile, error) {
+	if len(profiles) == 0 {
+		return Profile{}, fmt.Errorf("no profiles found")
+	}
+	for _, p := range profiles {
+		if p.Default {
+			return p, nil
+		}
+	}
+	return profiles[0], nil
+}
+
+// envProfile returns the single profile described by a *_HISTORY_PATH override.
+// Its Dir is the name of the directory holding the database, like the profiles
+// found on disk, so an override of a profile's own database archives under it.
+func envProfile(path string) Profile {
+	return Profile{Name: "Default", Dir: filepath.Base(filepath.Dir(path)), Path: path, Default: true}
+}
+
+// chromeLocalState is the part of Chromium's "Local State" file listing profiles.
+type chromeLocalState struct {
+	Profile struct {
+		InfoCache map[string]struct {
+			Name string `json:"name"`
+		} `json:"info_cache"`
+	} `json:"profile"`
+}
+
+// chromeProfiles lists the profiles in a Chromium user data directory using its
+// "Local State" file. Profiles without a History file are skipped. If Local State
+// is missing, only defaultDir is considered.
+func chromeProfiles(userDataDir, defaultDir string) ([]Profile, error) {
+	names := map[string]string{defaultDir: "Default"}
+
+	data, err := os.ReadFile(filepath.Join(userDataDir, "Local State"))
+	if err == nil {
+		var state chromeLocalState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, fmt.Errorf("failed to parse Local State: %w", err)
+		}
+		for dir, info := range state.Profile.InfoCache {
+			names[dir] = info.Name
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read Local State: %w", err)
+	}
+
+	var profiles []Profile
+	for dir, name := range names {
+		path := filepath.Join(userDataDir, dir, "History")
+		if !fileExists(path) {
+			continue
+		}
+		if name == "" {
+			name = dir
+		}
+		profiles = append(profiles, Profile{
+			Name:    name,
+			Dir:     dir,
+			Path:    path,
+			Default: dir == defaultDir,
+		})
+	}
+
+	sortProfiles(profiles)
+	return profiles, nil
+}
+
+// geckoProfiles lists the profiles under a Gecko profile root (the directory
+// holding profiles.ini). Profiles without a places.sqlite file are skipped. If
+// profiles.ini is missing, profile directories ending in .default or
+// .default-release are picked up instead.
+func geckoProfiles(root string) ([]Profile, error) {
+	f, err := os.Open(filepath.Join(root, "profiles.ini"))
+	if os.IsNotExist(err) {
+		return scanGeckoProfiles(root)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles.ini: %w", err)
+	}
+	defer f.Close()
+
+	sections, err := parseINI(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profiles.ini: %w", err)
+	}
+
+	// Newer releases record the default profile per installation rather than
+	// with Default=1 on the profile itself.
+	installDefaults := make(map[string]bool)
+	for _, s := range sections {
+		if strings.HasPrefix(s.name, "Install") && s.values["Default"] != "" {
+			installDefaults[s.values["Default"]] = true
+		}
+	}
+
+	var profiles []Profile
+	for _, s := range sections {
+		if !strings.HasPrefix(s.name, "Profile") || s.values["Path"] == "" {
+			continue
+		}
+
+		relPath := s.values["Path"]
+		dir := filepath.FromSlash(relPath)
+		if s.values["IsRelative"] != "0" {
+			dir = filepath.Join(root, dir)
+		}
+
+		path := filepath.Join(dir, "places.sqlite")
+		if !fileExists(path) {
+			continue
+		}
+
+		name := s.values["Name"]
+		if name == "" {
+			name = filepath.Base(dir)
+		}
+		profiles = append(profiles, Profile{
+			Name:    name,
+			Dir:     filepath.Base(dir),
+			Path:    path,
+			Default: installDefaults[relPath] || (len(installDefaults) == 0 && s.values["Default"] == "1"),
+		})
+	}
+
+	sortProfiles(profiles)
+	return profiles, nil
+}
+
+// scanGeckoProfiles is the fallback used when profiles.ini is missing.
+func scanGeckoProfiles(root string) ([]Profile, error) {
+	var profiles []Profile
+	for _, dir := range []string{root, filepath.Join(root, "Profiles")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, d := range entries {
+			ext := filepath.Ext(d.Name())
+			if !d.IsDir() || (ext != ".default-release" && ext != ".default") {
+				continue
+			}
+			path := filepath.Join(dir, d.Name(), "places.sqlite")
+			if !fileExists(path) {
+				continue
+			}
+			profiles = append(profiles, Profile{
+				Name:    strings.TrimPrefix(ext, "."),
+				Dir:     d.Name(),
+				Path:    path,
+				Default: ext == ".default-release",
+			})
+		}
+	}
+
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("could not find a default(-release) profile in %s", root)
+	}
+	sortProfiles(profiles)
+	return profiles, nil
+}
+
+// sortProfiles puts the default profile first and orders the rest by name.
+func sortProfiles(profiles []Profile) {
+	sort.SliceStable(profiles, func(i, j int) bool {
+		if profiles[i].Default != profiles[j].Default {
+			return profiles[i].Default
+		}
+		return profiles[i].Name < profiles[j].Name
+	})
+}
+
+type iniSection struct {
+	name   string
+	values map[string]string
+}
+
+// parseINI reads the small subset of INI used by profiles.ini: [sections],
+// key=value pairs and ; or # comments.
+func parseINI(r io.Reader) ([]iniSection, error) {
+	var sections []iniSection
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, ";"), strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			sections = append(sections, iniSection{
+				name:   strings.TrimSpace(line[1 : len(line)-1]),
+				values: make(map[string]string),
+			})
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok || len(sections) == 0 {
+				continue
+			}
+			sections[len(sections)-1].values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return sections, scanner.Err()
+}
@@ -0,0 +1,76 @@
+package parse
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// snapshotSuffixes are the files SQLite keeps next to a database while it is in use.
+// Copying them along with the database keeps changes the browser has not
+// checkpointed yet.
+var snapshotSuffixes = []string{"", "-wal", "-journal"}
+
+// Snapshot is a private copy of a browser history database. Reading from the copy
+// avoids "database is locked" errors while the browser is running and guarantees
+// the live profile is never written to.
+type Snapshot struct {
+	*sql.DB
+	dir string
+}
+
+// Close closes the database and removes the copy.
+func (s *Snapshot) Close() error {
+	return errors.Join(s.DB.Close(), os.RemoveAll(s.dir))
+}
+
+// openSnapshot copies the SQLite database at path, along with its -wal and
+// -journal files, into a private temporary directory and opens the copy.
+func openSnapshot(path, browser string) (*Snapshot, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("%s history database not found: %w", browser, err)
+	}
+
+	dir, err := os.MkdirTemp("", "histograph-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	copyPath := filepath.Join(dir, filepath.Base(path))
+	for _, suffix := range snapshotSuffixes {
+		err := copyFile(path+suffix, copyPath+suffix)
+		if err == nil || (suffix != "" && os.IsNotExist(err)) {
+			continue
+		}
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to snapshot %s history database: %w", browser, err)
+	}
+
+	db, err := sql.Open("sqlite3", copyPath)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to open %s history database: %w", browser, err)
+	}
+	return &Snapshot{DB: db, dir: dir}, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,180 @@
+package parse
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+// HistorySource is a browser whose history database Histograph knows how to read.
+type HistorySource interface {
+	// Name is the label shown in the browser menu, e.g. "Chrome".
+	Name() string
+	// Description is a short line shown under the name in the browser menu.
+	Description() string
+	// Detect reports whether at least one profile's history database exists on this machine.
+	Detect() bool
+	// Profiles lists the browser profiles that have a history database.
+	Profiles() ([]Profile, error)
+	// Open opens a private snapshot of a profile's history database.
+	Open(profile Profile) (*Snapshot, error)
+	// Entries reads the visits matching opts from a database returned by Open.
+	Entries(db *sql.DB, opts Options) ([]types.VisitEntry, error)
+}
+
+var registry []HistorySource
+
+// Register adds a source to the registry. Sources are listed in registration order.
+func Register(src HistorySource) {
+	for _, s := range registry {
+		if s.Name() == src.Name() {
+			panic(fmt.Sprintf("parse: source %q registered twice", src.Name()))
+		}
+	}
+	registry = append(registry, src)
+}
+
+// Sources returns every registered source.
+func Sources() []HistorySource {
+	sources := make([]HistorySource, len(registry))
+	copy(sources, registry)
+	return sources
+}
+
+// DetectedSources returns the registered sources whose history database was found.
+func DetectedSources() []HistorySource {
+	var sources []HistorySource
+	for _, s := range registry {
+		if s.Detect() {
+			sources = append(sources, s)
+		}
+	}
+	return sources
+}
+
+// Lookup returns the registered source with the given name.
+func Lookup(name string) (HistorySource, bool) {
+	for _, s := range registry {
+		if s.Name() == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// Parse reads the entries matching opts from the given profiles of a source, most
+// recent first. With no profiles, the source's default profile is read.
+func Parse(src HistorySource, opts Options, profiles ...Profile) ([]types.VisitEntry, error) {
+	if len(profiles) == 0 {
+		all, err := src.Profiles()
+		if err != nil {
+			return nil, err
+		}
+		profile, err := DefaultProfile(all)
+		if err != nil {
+			return nil, fmt.Errorf("no %s profile found: %w", src.Name(), err)
+		}
+		profiles = []Profile{profile}
+	}
+
+	var history []types.VisitEntry
+	for _, profile := range profiles {
+		entries, err := parseProfile(src, profile, opts)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", profile.Name, err)
+		}
+		history = append(history, entries...)
+	}
+
+	if len(profiles) > 1 {
+		sort.SliceStable(history, func(i, j int) bool {
+			return history[i].VisitTime.After(history[j].VisitTime)
+		})
+	}
+	return history, nil
+}
+
+func parseProfile(src HistorySource, profile Profile, opts Options) ([]types.VisitEntry, error) {
+	snap, err := src.Open(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer snap.Close()
+
+	entries, err := src.Entries(snap.DB, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Browser = src.Name()
+		entries[i].Profile = profile.Name
+		entries[i].ProfileDir = profile.Dir
+	}
+	return entries, nil
+}
+
+// AllBrowsers is the menu entry that reads every detected source at once.
+const AllBrowsers = "All browsers"
+
+// ParseAll reads every profile of every detected source concurrently and merges
+// the entries, most recent first. Sources that fail are skipped and reported in
+// the returned error, so callers may get both entries and an error.
+func ParseAll(opts Options) ([]types.VisitEntry, error) {
+	sources := DetectedSources()
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no browser history detected")
+	}
+
+	results := make([][]types.VisitEntry, len(sources))
+	errs := make([]error, len(sources))
+
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			profiles, err := src.Profiles()
+			if err == nil {
+				results[i], err = Parse(src, opts, profiles...)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", src.Name(), err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var history []types.VisitEntry
+	for _, entries := range results {
+		history = append(history, entries...)
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].VisitTime.After(history[j].VisitTime)
+	})
+	return history, errors.Join(errs...)
+}
+
+// detect reports whether src has at least one profile with a history database.
+func detect(src HistorySource) bool {
+	profiles, err := src.Profiles()
+	if err != nil {
+		return false
+	}
+	for _, p := range profiles {
+		if fileExists(p.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileExists reports whether path names an existing regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
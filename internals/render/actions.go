@@ -0,0 +1,191 @@
+// render/actions.go
+package render
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// actionMsg reports the result of an action on the selected Details entry.
+type actionMsg struct {
+	status string
+	err    error
+}
+
+// OpenURL opens rawURL in the default browser with xdg-open, open or, on
+// Windows, the URL protocol handler. Only web and file URLs are opened, so a
+// history entry cannot run arbitrary commands.
+func OpenURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "ftp", "file":
+	default:
+		return fmt.Errorf("cannot open %s URLs", u.Scheme)
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", rawURL)
+	case "windows":
+		// start would split the URL at every &
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", rawURL)
+	default:
+		cmd = exec.Command("xdg-open", rawURL)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// CopyToClipboard copies text to the system clipboard. Without a clipboard
+// tool, e.g. over SSH, it asks the terminal to do it with an OSC 52 sequence.
+func CopyToClipboard(text string) error {
+	if err := clipboard.WriteAll(text); err == nil {
+		return nil
+	}
+
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+	if _, err := seq.WriteTo(os.Stderr); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	return nil
+}
+
+// togglePane shows pane in Details instead of the list, or goes back to the
+// list if it is already shown.
+func (m *ChromeHistoryModel) togglePane(pane string) {
+	if m.currentView != "details" || len(m.historyData) == 0 {
+		return
+	}
+	if m.pane == pane {
+		m.pane = ""
+	} else {
+		m.pane = pane
+	}
+	m.updateContent()
+	if m.pane == "trail" {
+		m.viewport.GotoTop()
+	}
+}
+
+// openSelected opens the selected Details entry in the browser.
+func (m ChromeHistoryModel) openSelected() tea.Cmd {
+	entry, ok := m.selectedEntry()
+	if !ok {
+		return nil
+	}
+	open, url := m.open, m.originalURL(entry)
+	return func() tea.Msg {
+		if err := open(url); err != nil {
+			return actionMsg{err: err}
+		}
+		return actionMsg{status: "🚀 Opened " + url}
+	}
+}
+
+// copySelected copies the URL of the selected Details entry.
+func (m ChromeHistoryModel) copySelected() tea.Cmd {
+	entry, ok := m.selectedEntry()
+	if !ok {
+		return nil
+	}
+	copyText, url := m.copyText, m.originalURL(entry)
+	return func() tea.Msg {
+		if err := copyText(url); err != nil {
+			return actionMsg{err: err}
+		}
+		return actionMsg{status: "📋 Copied " + url}
+	}
+}
+
+// urlVisits returns every loaded visit of the canonical url, most recent first.
+func (m ChromeHistoryModel) urlVisits(url string) []types.VisitEntry {
+	var visits []types.VisitEntry
+	for _, entry := range m.allData {
+		if entry.URL == url {
+			visits = append(visits, entry)
+		}
+	}
+	sort.SliceStable(visits, func(i, j int) bool {
+		return visits[i].VisitTime.After(visits[j].VisitTime)
+	})
+	return visits
+}
+
+// renderVisits lists every visit of the selected entry's URL, marking the
+// selected visit, and returns the line of that visit.
+func (m ChromeHistoryModel) renderVisits() (string, int) {
+	selected, ok := m.selectedEntry()
+	if !ok {
+		return cardStyle.Render("No entry selected"), -1
+	}
+	visits := m.urlVisits(selected.URL)
+
+	title := truncateString(selected.Title, 60)
+	if title == "" {
+		title = "Untitled"
+	}
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render("🔁 Every Visit") + "\n\n")
+	content.WriteString(highlightStyle.Render(title) + "\n")
+	url := m.originalURL(selected)
+	content.WriteString(dimStyle.Render(url) + "\n")
+	first, last := visits[len(visits)-1].VisitTime, visits[0].VisitTime
+	content.WriteString(dimStyle.Render(fmt.Sprintf("%d visits loaded • first %s • last %s",
+		len(visits), first.In(m.loc).Format("Jan 2, 2006"), last.In(m.loc).Format("Jan 2, 2006"))) + "\n\n")
+
+	line := -1
+	for _, visit := range visits {
+		details := visit.Browser
+		if visit.Profile != "" {
+			details += " (" + visit.Profile + ")"
+		}
+		if visit.Transition != types.TransitionUnknown {
+			details += " • " + strings.ReplaceAll(string(visit.Transition), "_", " ")
+		}
+		if visit.Duration > 0 {
+			details += " • " + formatDuration(visit.Duration)
+		}
+		// Visits are listed by canonical URL, so name the ones made at another.
+		if visitURL := m.originalURL(visit); visitURL != url {
+			details += " • " + truncateString(visitURL, 60)
+		}
+
+		when := visit.VisitTime.In(m.loc).Format("Mon Jan 2 2006, 15:04")
+		if sameVisit(visit, selected) {
+			line = lineOf(&content)
+			content.WriteString(headerStyle.Render(selectionMarker+" "+when) + " " + dimStyle.Render(details) + "\n")
+		} else {
+			content.WriteString("  " + highlightStyle.Render(when) + " " + dimStyle.Render(details) + "\n")
+		}
+	}
+
+	return cardStyle.Render(content.String()), line
+}
+
+// sameVisit reports whether a and b are the same visit of the same profile.
+func sameVisit(a, b types.VisitEntry) bool {
+	return a.Key() == b.Key() && a.VisitTime.Equal(b.VisitTime)
+}
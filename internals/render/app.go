@@ -0,0 +1,223 @@
+// render/app.go
+package render
+
+import (
+	"fmt"
+
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true)
+
+	promptStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("228")).
+			Bold(true)
+)
+
+// Loader reads the history selected in the browser menu.
+type Loader func(BrowserChoice) ([]types.VisitEntry, error)
+
+// appState is the screen the app is showing.
+type appState int
+
+const (
+	stateMenu appState = iota
+	stateLoading
+	stateError
+	stateViewer
+)
+
+// browserChosenMsg is sent by the menu once a browser and its profiles were picked.
+type browserChosenMsg BrowserChoice
+
+// backToMenuMsg is sent by the viewer to pick another browser.
+type backToMenuMsg struct{}
+
+// loadedMsg carries the result of a Loader call. id matches AppModel.loadID
+// of the load that was started, so results of abandoned loads are dropped.
+type loadedMsg struct {
+	id      int
+	entries []types.VisitEntry
+	err     error
+}
+
+// AppModel is the root model of the TUI. It moves between the browser menu,
+// loading, error and viewer screens within a single program, so browsers can
+// be switched without restarting.
+type AppModel struct {
+	state   appState
+	menu    model
+	viewer  ChromeHistoryModel
+	spinner spinner.Model
+	load    Loader
+	opts    ViewerOptions
+	choice  BrowserChoice
+	err     error
+	loadID  int
+	width   int
+	height  int
+}
+
+// NewAppModel creates the root model. With an empty choice it starts at the
+// browser menu, otherwise it loads that choice straight away.
+func NewAppModel(load Loader, choice BrowserChoice, opts ViewerOptions) AppModel {
+	sp := spinner.New()
+	sp.Style = highlightStyle
+
+	m := AppModel{
+		state:   stateMenu,
+		menu:    NewModel(),
+		spinner: sp,
+		load:    load,
+		opts:    opts,
+		choice:  choice,
+		width:   120,
+		height:  40,
+	}
+	if choice.Browser != "" {
+		m.state = stateLoading
+	}
+	return m
+}
+
+func (m AppModel) Init() tea.Cmd {
+	if m.state == stateLoading {
+		return tea.Batch(m.spinner.Tick, m.loadCmd())
+	}
+	return nil
+}
+
+// loadCmd reads m.choice in the background.
+func (m AppModel) loadCmd() tea.Cmd {
+	id, load, choice := m.loadID, m.load, m.choice
+	return func() tea.Msg {
+		entries, err := load(choice)
+		return loadedMsg{id: id, entries: entries, err: err}
+	}
+}
+
+// startLoading switches to the loading screen and starts loading m.choice.
+func (m AppModel) startLoading() (tea.Model, tea.Cmd) {
+	m.state = stateLoading
+	m.err = nil
+	m.loadID++
+	return m, tea.Batch(m.spinner.Tick, m.loadCmd())
+}
+
+// showMenu switches back to the browser list.
+func (m AppModel) showMenu() (tea.Model, tea.Cmd) {
+	m.state = stateMenu
+	m.loadID++ // drop the result of a load still running
+	m.menu.reset()
+	return m, nil
+}
+
+func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if m.state == stateViewer {
+			return m.updateViewer(msg)
+		}
+		return m, nil
+
+	case browserChosenMsg:
+		m.choice = BrowserChoice(msg)
+		return m.startLoading()
+
+	case backToMenuMsg:
+		return m.showMenu()
+
+	case loadedMsg:
+		if msg.id != m.loadID || m.state != stateLoading {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.state = stateError
+			m.err = msg.err
+			return m, nil
+		}
+		if len(msg.entries) == 0 {
+			m.state = stateError
+			m.err = fmt.Errorf("no %s history found", m.choice)
+			return m, nil
+		}
+		m.state = stateViewer
+		m.viewer = NewChromeHistoryModel(msg.entries, m.opts, m.width, m.height)
+		return m.updateViewer(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+
+	case spinner.TickMsg:
+		if m.state != stateLoading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	switch m.state {
+	case stateMenu:
+		var cmd tea.Cmd
+		m.menu, cmd = m.menu.update(msg)
+		return m, cmd
+	case stateLoading:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "q", "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				return m.showMenu()
+			}
+		}
+	case stateError:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "q", "ctrl+c":
+				return m, tea.Quit
+			case "enter", "r":
+				return m.startLoading()
+			case "esc", "b":
+				return m.showMenu()
+			}
+		}
+	case stateViewer:
+		return m.updateViewer(msg)
+	}
+	return m, nil
+}
+
+func (m AppModel) updateViewer(msg tea.Msg) (tea.Model, tea.Cmd) {
+	viewer, cmd := m.viewer.Update(msg)
+	m.viewer = viewer.(ChromeHistoryModel)
+	return m, cmd
+}
+
+func (m AppModel) View() string {
+	switch m.state {
+	case stateLoading:
+		return cardStyle.Render(m.spinner.View() + " " + highlightStyle.Render("Fetching "+m.choice.String()+" history...") +
+			"\n\n" + dimStyle.Render("Press esc to pick another browser or q to quit"))
+	case stateError:
+		return cardStyle.Render(errorStyle.Render("❌ Error: "+m.err.Error()) + "\n\n" +
+			dimStyle.Render("Make sure the history file exists and is readable, or point the browser's *_HISTORY_PATH variable at it.") + "\n\n" +
+			promptStyle.Render("Press enter to retry, esc to pick another browser or q to quit"))
+	case stateViewer:
+		return m.viewer.View()
+	}
+	return m.menu.View()
+}
+
+// Run starts the TUI and returns once the user quits. With an empty choice it
+// starts at the browser menu.
+func Run(load Loader, choice BrowserChoice, opts ViewerOptions) error {
+	p := tea.NewProgram(NewAppModel(load, choice, opts), tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
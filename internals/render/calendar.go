@@ -0,0 +1,153 @@
+// render/calendar.go
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// calendarWeeks is how many weeks the calendar shows at once.
+const calendarWeeks = 53
+
+var calendarCursorStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FAFAFA")).
+	Background(lipgloss.Color("#F25D94")).
+	Bold(true)
+
+// calendarView is the state of the Calendar view. Days are midnight UTC
+// dates, so stepping through them is not affected by DST; they stand for
+// dates in the model's time zone.
+type calendarView struct {
+	cursor time.Time // selected day; zero until the view is first shown
+	end    time.Time // last day shown
+}
+
+// calendarDay returns the date of t in loc as midnight UTC.
+func calendarDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// resetCalendar selects the most recent day with visits, or today.
+func (m *ChromeHistoryModel) resetCalendar() {
+	day := calendarDay(time.Now(), m.loc)
+	if dates, _ := GroupByDate(m.calendarData, m.loc); len(dates) > 0 {
+		day, _ = time.Parse("2006-01-02", dates[len(dates)-1])
+	}
+	m.calendar = calendarView{cursor: day, end: day}
+}
+
+// moveCalendar moves the cursor by days and scrolls the calendar to keep it
+// in view.
+func (m *ChromeHistoryModel) moveCalendar(days int) {
+	c := &m.calendar
+	c.cursor = c.cursor.AddDate(0, 0, days)
+	if c.cursor.After(c.end) {
+		c.end = c.cursor
+	}
+	if start := c.end.AddDate(0, 0, -7*(calendarWeeks-1)-weekdayIndex(c.end)); c.cursor.Before(start) {
+		// Make the cursor's week the first one shown
+		c.end = c.cursor.AddDate(0, 0, 7*(calendarWeeks-1))
+	}
+}
+
+// weekdayIndex returns the row of t in the calendar, Monday first.
+func weekdayIndex(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+// updateCalendar handles the Calendar view's own keys and reports whether it
+// consumed msg.
+func (m *ChromeHistoryModel) updateCalendar(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "left", "h":
+		m.moveCalendar(-7)
+	case "right", "l":
+		m.moveCalendar(7)
+	case "up", "k":
+		m.moveCalendar(-1)
+	case "down", "j":
+		m.moveCalendar(1)
+	case "enter":
+		// Jump to the day's visits
+		m.dayFilter = m.calendar.cursor
+		m.currentView = "details"
+		m.applyFilters()
+		return true
+	default:
+		return false
+	}
+	m.updateContent()
+	return true
+}
+
+func (m ChromeHistoryModel) renderCalendar() string {
+	_, groups := GroupByDate(m.calendarData, m.loc)
+	c := m.calendar
+
+	// Columns are weeks from Monday to Sunday, the last one holding c.end
+	start := c.end.AddDate(0, 0, -7*(calendarWeeks-1)-weekdayIndex(c.end))
+	peak := 0
+	for day := start; !day.After(c.end); day = day.AddDate(0, 0, 1) {
+		peak = max(peak, len(groups[day.Format("2006-01-02")]))
+	}
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render(fmt.Sprintf("📆 Calendar %s – %s",
+		start.Format("Jan 2, 2006"), c.end.Format("Jan 2, 2006"))) + "\n\n")
+
+	// Month labels over the first week of each month
+	months := []rune(strings.Repeat(" ", calendarWeeks*2))
+	for week := 0; week < calendarWeeks; week++ {
+		monday := start.AddDate(0, 0, 7*week)
+		if week == 0 || monday.Day() <= 7 {
+			label := monday.Format("Jan")
+			if week*2+len(label) <= len(months) {
+				copy(months[week*2:], []rune(label))
+			}
+		}
+	}
+	content.WriteString("     " + dimStyle.Render(strings.TrimRight(string(months), " ")) + "\n")
+
+	for row := 0; row < 7; row++ {
+		label := "    "
+		if row%2 == 0 {
+			label = weekdays[row].String()[:3] + " "
+		}
+		content.WriteString(dimStyle.Render(label) + " ")
+		for week := 0; week < calendarWeeks; week++ {
+			day := start.AddDate(0, 0, 7*week+row)
+			switch {
+			case day.After(c.end):
+				content.WriteString("  ")
+			case day.Equal(c.cursor):
+				content.WriteString(calendarCursorStyle.Render("◆ "))
+			default:
+				content.WriteString(heatCell(len(groups[day.Format("2006-01-02")]), peak))
+			}
+		}
+		content.WriteString("\n")
+	}
+
+	// Legend
+	content.WriteString("\n     " + dimStyle.Render("less "))
+	for i := range heatRamp {
+		content.WriteString(lipgloss.NewStyle().Background(heatRamp[i]).Render("  "))
+	}
+	content.WriteString(dimStyle.Render(" more") + "\n\n")
+
+	// Selected day
+	visits := groups[c.cursor.Format("2006-01-02")]
+	content.WriteString(highlightStyle.Render(c.cursor.Format("Mon Jan 2, 2006")) + fmt.Sprintf(": %d visits", len(visits)))
+	if sites := stats.TopSites(visits); len(sites) > 0 {
+		content.WriteString(" • top: " + sites[0].Domain)
+	}
+	content.WriteString("\n")
+
+	return cardStyle.Render(content.String())
+}
@@ -0,0 +1,51 @@
+// render/canonical.go
+package render
+
+import (
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	"github.com/akshatsrivastava11/Histograph/internals/urlnorm"
+)
+
+// visitKey identifies a visit for originalURL. VisitTime and URL tell apart
+// entries without visit IDs.
+type visitKey struct {
+	types.VisitKey
+	time int64
+	url  string
+}
+
+func keyOf(entry types.VisitEntry) visitKey {
+	return visitKey{entry.Key(), entry.VisitTime.UnixNano(), entry.URL}
+}
+
+// canonicalize returns a copy of entries with canonical URLs, so that every
+// view aggregates the same page under one URL, and the original URLs that
+// changed.
+func canonicalize(entries []types.VisitEntry, rules *urlnorm.Rules) ([]types.VisitEntry, map[visitKey]string) {
+	r := urlnorm.DefaultRules()
+	if rules != nil {
+		r = *rules
+	}
+
+	canonical := make([]types.VisitEntry, len(entries))
+	originals := make(map[visitKey]string)
+	for i, entry := range entries {
+		url := r.Canonicalize(entry.URL)
+		if url != entry.URL {
+			original := entry.URL
+			entry.URL = url
+			originals[keyOf(entry)] = original
+		}
+		canonical[i] = entry
+	}
+	return canonical, originals
+}
+
+// originalURL returns the URL of entry as the browser recorded it. It is what
+// the views show, open and copy; the canonical URL only groups visits.
+func (m ChromeHistoryModel) originalURL(entry types.VisitEntry) string {
+	if url, ok := m.originals[keyOf(entry)]; ok {
+		return url
+	}
+	return entry.URL
+}
@@ -5,8 +5,13 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/akshatsrivastava11/Histograph/internals/graph"
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
 	"github.com/akshatsrivastava11/Histograph/internals/types"
+	"github.com/akshatsrivastava11/Histograph/internals/urlnorm"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -15,12 +20,33 @@ import (
 // ChromeHistoryModel represents the state for Chrome history visualization
 type ChromeHistoryModel struct {
 	viewport     viewport.Model
-	historyData  []types.VisitEntry
-	currentView  string // "overview", "timeline", "sites", "details"
+	allData      []types.VisitEntry
+	historyData  []types.VisitEntry // calendarData narrowed to dayFilter
+	calendarData []types.VisitEntry // allData narrowed to the selected date range and search
+	details      []types.VisitEntry // historyData most recent first, as Details lists it
+	detailsTop   int                // first entry of details on screen
+	dayFilter    time.Time          // calendar day to show, or zero for every day
+	dateRange    int                // index into dateRanges
+	search       textinput.Model    // focused while the user types a search
+	currentView  string             // "overview", "timeline", "sites", "details", "sessions", "heatmap", "calendar"
+	loc          *time.Location     // time zone used to display and group visits
 	selectedItem int
-	ready        bool
-	width        int
-	height       int
+	sessions     sessionsView
+	// heatmapDomain indexes heatmapDomainList; 0 shows every domain
+	heatmapDomain int
+	calendar      calendarView
+	siteItem      int    // selected row of Top Sites
+	domain        string // domain whose page Top Sites shows, if any
+	grouping      stats.Grouping
+	graph         *graph.Graph        // referrers of allData
+	originals     map[visitKey]string // URLs of allData before canonicalisation, where they differ
+	pane          string              // "trail" or "visits" to show instead of the Details list
+	open          func(url string) error
+	copyText      func(text string) error
+	status        string // result of the last action, until the next key
+	ready         bool
+	width         int
+	height        int
 }
 
 // Styles for the UI
@@ -57,19 +83,77 @@ var (
 			MarginBottom(1)
 )
 
-// NewChromeHistoryModel creates a new Chrome history visualization model
-func NewChromeHistoryModel(historyData []types.VisitEntry, width, height int) ChromeHistoryModel {
+// dateRanges are the presets offered by the date range selector
+var dateRanges = []struct {
+	label string
+	days  int // 0 keeps everything that was loaded
+}{
+	{"All loaded", 0},
+	{"Today", 1},
+	{"Last 7 days", 7},
+	{"Last 30 days", 30},
+	{"Last 90 days", 90},
+	{"Last year", 365},
+}
+
+// ViewerOptions configures the history viewer.
+type ViewerOptions struct {
+	// Location is the time zone visits are displayed and grouped by day in.
+	// Nil means local time.
+	Location *time.Location
+	// SessionGap is the inactivity that ends a session in the Sessions view.
+	// Zero means stats.DefaultSessionGap.
+	SessionGap time.Duration
+	// Open opens a URL from Details. Nil means OpenURL.
+	Open func(url string) error
+	// Copy copies a URL from Details. Nil means CopyToClipboard.
+	Copy func(text string) error
+	// URLRules canonicalise URLs before they are aggregated, so the same page
+	// is counted once. Nil means urlnorm.DefaultRules.
+	URLRules *urlnorm.Rules
+}
+
+// NewChromeHistoryModel creates a new Chrome history visualization model.
+func NewChromeHistoryModel(historyData []types.VisitEntry, opts ViewerOptions, width, height int) ChromeHistoryModel {
+	historyData, originals := canonicalize(historyData, opts.URLRules)
 	vp := viewport.New(70-4, 100-6)
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	search := textinput.New()
+	search.Prompt = "/"
+	search.Placeholder = "search titles, URLs and domains"
 
 	m := ChromeHistoryModel{
 		viewport:     vp,
+		search:       search,
+		allData:      historyData,
 		historyData:  historyData,
+		calendarData: historyData,
+		details:      newestFirst(historyData),
 		currentView:  "overview",
+		loc:          loc,
 		selectedItem: 0,
+		sessions:     sessionsView{gap: opts.SessionGap, open: -1},
+		grouping:     stats.ByHost,
+		open:         opts.Open,
+		copyText:     opts.Copy,
+		graph:        graph.Build(historyData),
+		originals:    originals,
 		width:        width,
 		height:       height,
 	}
 
+	if m.open == nil {
+		m.open = OpenURL
+	}
+	if m.copyText == nil {
+		m.copyText = CopyToClipboard
+	}
+
+	m.sessions.build(historyData)
 	m.updateContent()
 	return m
 }
@@ -81,9 +165,39 @@ func (m ChromeHistoryModel) Init() tea.Cmd {
 func (m ChromeHistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.status = ""
+		if m.search.Focused() {
+			return m.updateSearch(msg)
+		}
+		if m.currentView == "sessions" && m.updateSessions(msg) {
+			return m, nil
+		}
+		if m.currentView == "calendar" && m.updateCalendar(msg) {
+			return m, nil
+		}
+		if m.currentView == "sites" && m.updateSites(msg) {
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
+		case "b":
+			return m, func() tea.Msg { return backToMenuMsg{} }
+		case "/":
+			m.search.CursorEnd()
+			return m, m.search.Focus()
+		case "esc":
+			if m.pane != "" {
+				m.pane = ""
+				m.updateContent()
+			} else if !m.dayFilter.IsZero() {
+				m.dayFilter = time.Time{}
+				m.applyFilters()
+			} else if m.search.Value() != "" {
+				m.search.SetValue("")
+				m.applyFilters()
+			}
 		case "1":
 			m.currentView = "overview"
 			m.updateContent()
@@ -96,15 +210,61 @@ func (m ChromeHistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "4":
 			m.currentView = "details"
 			m.updateContent()
+		case "5":
+			m.currentView = "sessions"
+			m.updateContent()
+		case "6":
+			m.currentView = "heatmap"
+			m.updateContent()
+		case "7":
+			if m.calendar.cursor.IsZero() {
+				m.resetCalendar()
+			}
+			m.currentView = "calendar"
+			m.updateContent()
+		case "d":
+			if m.currentView == "heatmap" {
+				m.cycleHeatmapDomain(1)
+			}
+		case "D":
+			if m.currentView == "heatmap" {
+				m.cycleHeatmapDomain(-1)
+			}
+		case "r":
+			m.dateRange = (m.dateRange + 1) % len(dateRanges)
+			m.applyFilters()
+		case "R":
+			m.dateRange = (m.dateRange + len(dateRanges) - 1) % len(dateRanges)
+			m.applyFilters()
 		case "up", "k":
 			if m.selectedItem > 0 {
 				m.selectedItem--
+				m.updateDetails()
 			}
 		case "down", "j":
-			if m.selectedItem < len(m.historyData)-1 {
+			if m.selectedItem < len(m.details)-1 {
 				m.selectedItem++
+				m.updateDetails()
+			}
+		case "g":
+			m.togglePane("trail")
+		case "enter":
+			m.togglePane("visits")
+		case "o":
+			if m.currentView == "details" {
+				return m, m.openSelected()
 			}
+		case "c":
+			if m.currentView == "details" {
+				return m, m.copySelected()
+			}
+		}
+	case actionMsg:
+		m.status = msg.status
+		if msg.err != nil {
+			m.status = "❌ " + msg.err.Error()
 		}
+		return m, nil
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -118,6 +278,31 @@ func (m ChromeHistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateSearch handles keys while the search prompt is focused. Every view is
+// narrowed live as the query changes; enter keeps it and esc clears it.
+func (m ChromeHistoryModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "enter":
+		m.search.Blur()
+		return m, nil
+	case "esc":
+		m.search.Blur()
+		m.search.SetValue("")
+		m.applyFilters()
+		return m, nil
+	}
+
+	before := m.search.Value()
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	if m.search.Value() != before {
+		m.applyFilters()
+	}
+	return m, cmd
+}
+
 func (m ChromeHistoryModel) View() string {
 	if !m.ready {
 		return "Loading Chrome history..."
@@ -125,13 +310,51 @@ func (m ChromeHistoryModel) View() string {
 
 	header := titleStyle.Render("🌐 Browser History Analyzer") + "\n\n"
 
-	nav := fmt.Sprintf("%s | %s | %s | %s\n\n",
+	dateLabel := dateRanges[m.dateRange].label
+	if !m.dayFilter.IsZero() {
+		dateLabel = m.dayFilter.Format("Mon Jan 2, 2006")
+	}
+
+	nav := fmt.Sprintf("%s | %s | %s | %s | %s | %s | %s    %s\n\n",
 		m.navItem("1", "Overview", m.currentView == "overview"),
 		m.navItem("2", "Timeline", m.currentView == "timeline"),
 		m.navItem("3", "Top Sites", m.currentView == "sites"),
-		m.navItem("4", "Details", m.currentView == "details"))
+		m.navItem("4", "Details", m.currentView == "details"),
+		m.navItem("5", "Sessions", m.currentView == "sessions"),
+		m.navItem("6", "Heatmap", m.currentView == "heatmap"),
+		m.navItem("7", "Calendar", m.currentView == "calendar"),
+		m.navItem("r", "📆 "+dateLabel, m.dateRange != 0 || !m.dayFilter.IsZero()))
+
+	footer := dimStyle.Render("Press 1-7 to switch views, r/R to change the date range, / to search, ↑/↓ to navigate, b to change browser, q to quit")
+	if m.currentView == "sessions" {
+		footer = dimStyle.Render("Press ↑/↓ to pick a session, enter to open it, esc to go back, 1-7 to switch views, q to quit")
+	} else if m.currentView == "sites" && m.domain != "" {
+		footer = dimStyle.Render("Press esc to go back to Top Sites, ↑/↓ to scroll, r/R to change the date range, 1-7 to switch views, q to quit")
+	} else if m.currentView == "sites" {
+		footer = dimStyle.Render("Press ↑/↓ to pick a site, enter to open its page, h to group by host or domain, r/R to change the date range, / to search, q to quit")
+	} else if m.currentView == "heatmap" {
+		footer = dimStyle.Render("Press d/D to filter by domain, 1-7 to switch views, r/R to change the date range, / to search, q to quit")
+	} else if m.currentView == "calendar" {
+		footer = dimStyle.Render("Press ←/→ to move by week, ↑/↓ by day, enter to list the day's visits, 1-7 to switch views, q to quit")
+	} else if m.currentView == "details" && m.pane == "trail" {
+		footer = dimStyle.Render("Press g or esc to go back to the list, o to open, c to copy the URL, 1-7 to switch views, q to quit")
+	} else if m.currentView == "details" && m.pane == "visits" {
+		footer = dimStyle.Render("Press enter or esc to go back to the list, o to open, c to copy the URL, 1-7 to switch views, q to quit")
+	} else if m.currentView == "details" {
+		footer = dimStyle.Render("Press ↑/↓ to select, enter for every visit, o to open, c to copy the URL, g to see how you got there, / to search, q to quit")
+	}
+	if !m.dayFilter.IsZero() && m.pane == "" {
+		footer = dimStyle.Render("Showing "+m.dayFilter.Format("Mon Jan 2, 2006")+", press esc to show every day or 7 to pick another") + "\n" + footer
+	}
+	if m.search.Focused() {
+		footer = m.search.View() + "\n" + dimStyle.Render("Press enter to keep the search, esc to clear it")
+	} else if m.search.Value() != "" {
+		footer = highlightStyle.Render("🔎 "+m.search.Value()) + dimStyle.Render(fmt.Sprintf(" (%d matches, esc to clear)", len(m.historyData))) + "\n" + footer
+	}
 
-	footer := dimStyle.Render("Press 1-4 to switch views, ↑/↓ to navigate, q to quit")
+	if m.status != "" {
+		footer = highlightStyle.Render(m.status) + "\n" + footer
+	}
 
 	content := header + nav + m.viewport.View() + "\n" + footer
 	return content
@@ -146,6 +369,7 @@ func (m *ChromeHistoryModel) navItem(key, text string, active bool) string {
 
 func (m *ChromeHistoryModel) updateContent() {
 	var content string
+	selected := -1 // line of the selected row in content, kept in view
 
 	switch m.currentView {
 	case "overview":
@@ -153,15 +377,97 @@ func (m *ChromeHistoryModel) updateContent() {
 	case "timeline":
 		content = m.renderTimeline()
 	case "sites":
-		content = m.renderTopSites()
+		if m.domain != "" {
+			content = m.renderDomain()
+		} else {
+			content, selected = m.renderTopSites()
+		}
 	case "details":
-		content = m.renderDetails()
+		switch m.pane {
+		case "trail":
+			content, selected = m.renderTrail()
+		case "visits":
+			content, selected = m.renderVisits()
+		default:
+			m.scrollDetails()
+			content, selected = m.renderDetails()
+		}
+	case "sessions":
+		content, selected = m.renderSessions()
+	case "heatmap":
+		content = m.renderHeatmap()
+	case "calendar":
+		content = m.renderCalendar()
 	}
 
 	m.viewport.SetContent(content)
+	if selected >= 0 {
+		m.scrollToLine(selected)
+	}
 	m.ready = true
 }
 
+// applyFilters narrows historyData to the selected date range and search, and
+// redraws the view
+func (m *ChromeHistoryModel) applyFilters() {
+	data := m.allData
+	if days := dateRanges[m.dateRange].days; days != 0 {
+		now := time.Now().In(m.loc)
+		cutoff := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, m.loc).AddDate(0, 0, 1-days)
+		data = nil
+		for _, entry := range m.allData {
+			if !entry.VisitTime.Before(cutoff) {
+				data = append(data, entry)
+			}
+		}
+	}
+	m.calendarData = FilterSearch(data, m.search.Value())
+	m.historyData = m.calendarData
+	if !m.dayFilter.IsZero() {
+		m.historyData = nil
+		for _, entry := range m.calendarData {
+			if calendarDay(entry.VisitTime, m.loc).Equal(m.dayFilter) {
+				m.historyData = append(m.historyData, entry)
+			}
+		}
+	}
+	m.details = newestFirst(m.historyData)
+	m.selectedItem = 0
+	m.detailsTop = 0
+	m.pane = ""
+	m.siteItem = 0
+	m.sessions.build(m.historyData)
+	m.heatmapDomain = 0
+	m.updateContent()
+}
+
+// FilterSearch returns the entries whose title, URL or domain contain every
+// whitespace-separated term of query, ignoring case. An empty query keeps all
+// entries.
+func FilterSearch(entries []types.VisitEntry, query string) []types.VisitEntry {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return entries
+	}
+
+	var matches []types.VisitEntry
+	for _, entry := range entries {
+		// The URL already contains the domain, so title and URL cover all three.
+		text := strings.ToLower(entry.Title + "\n" + entry.URL)
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
 func (m ChromeHistoryModel) renderOverview() string {
 	if len(m.historyData) == 0 {
 		return cardStyle.Render("No Chrome history data found")
@@ -171,24 +477,32 @@ func (m ChromeHistoryModel) renderOverview() string {
 	totalVisits := len(m.historyData)
 	totalVisitCount := 0
 	domains := make(map[string]int)
+	browsers := make(map[string]int)
 
 	for _, entry := range m.historyData {
 		totalVisitCount += entry.VisitCount
-		domain := extractDomain(entry.URL)
+		domain := stats.Domain(entry.URL)
 		domains[domain]++
+		browsers[entry.Browser]++
 	}
 
 	// Create overview content
-	stats := fmt.Sprintf("📊 Total Entries: %d\n", totalVisits) +
+	summary := fmt.Sprintf("📊 Total Entries: %d\n", totalVisits) +
 		fmt.Sprintf("🔄 Total Visits: %d\n", totalVisitCount) +
 		fmt.Sprintf("🌐 Unique Domains: %d\n", len(domains))
 
 	// Create a simple bar chart for top domains
 	chart := m.createDomainChart(domains)
 
-	overview := cardStyle.Render(headerStyle.Render("📈 Statistics") + "\n\n" + stats)
+	overview := cardStyle.Render(headerStyle.Render("📈 Statistics") + "\n\n" + summary)
 	chartCard := chartStyle.Render(headerStyle.Render("🔝 Top Domains") + "\n\n" + chart)
 
+	// Break entries down per browser when several were merged
+	if len(browsers) > 1 {
+		browserCard := chartStyle.Render(headerStyle.Render("🧭 Browsers") + "\n\n" + m.createDomainChart(browsers))
+		return overview + "\n" + browserCard + "\n" + chartCard
+	}
+
 	return overview + "\n" + chartCard
 }
 
@@ -198,18 +512,7 @@ func (m ChromeHistoryModel) renderTimeline() string {
 	}
 
 	// Group visits by date
-	dateGroups := make(map[string][]types.VisitEntry)
-	for _, entry := range m.historyData {
-		date := entry.VisitTime.Format("2006-01-02")
-		dateGroups[date] = append(dateGroups[date], entry)
-	}
-
-	// Sort dates
-	var dates []string
-	for date := range dateGroups {
-		dates = append(dates, date)
-	}
-	sort.Strings(dates)
+	dates, dateGroups := GroupByDate(m.historyData, m.loc)
 
 	// Create timeline visualization
 	var timeline strings.Builder
@@ -242,117 +545,159 @@ func (m ChromeHistoryModel) renderTimeline() string {
 	return cardStyle.Render(timeline.String())
 }
 
-func (m ChromeHistoryModel) renderTopSites() string {
+// renderTopSites lists the most visited sites and returns the line of the
+// selected one.
+func (m ChromeHistoryModel) renderTopSites() (string, int) {
 	if len(m.historyData) == 0 {
-		return cardStyle.Render("No sites data available")
+		return cardStyle.Render("No sites data available"), -1
 	}
 
-	// Aggregate by domain
-	domainData := make(map[string]struct {
-		visits int
-		count  int
-		title  string
-	})
-
+	sites := stats.TopSitesBy(m.historyData, m.grouping)
+	allBrowsers := make(map[string]bool)
 	for _, entry := range m.historyData {
-		domain := extractDomain(entry.URL)
-		data := domainData[domain]
-		data.visits += entry.VisitCount
-		data.count++
-		if data.title == "" {
-			data.title = entry.Title
-		}
-		domainData[domain] = data
-	}
-
-	// Sort by visits
-	type siteData struct {
-		domain string
-		visits int
-		count  int
-		title  string
+		allBrowsers[entry.Browser] = true
 	}
 
-	var sites []siteData
-	for domain, data := range domainData {
-		sites = append(sites, siteData{
-			domain: domain,
-			visits: data.visits,
-			count:  data.count,
-			title:  data.title,
-		})
-	}
-
-	sort.Slice(sites, func(i, j int) bool {
-		return sites[i].visits > sites[j].visits
-	})
-
 	// Create top sites display
 	var content strings.Builder
-	content.WriteString(headerStyle.Render("🏆 Top Sites") + "\n\n")
+	content.WriteString(headerStyle.Render("🏆 Top Sites") + dimStyle.Render("  by "+string(m.grouping)) + "\n\n")
 
+	selected := -1
 	for i, site := range sites {
-		if i >= 15 { // Show top 15
+		if i >= topSitesShown {
 			break
 		}
 
 		rank := fmt.Sprintf("%2d.", i+1)
-		bar := m.createVisitBar(site.visits, sites[0].visits, 20)
+		bar := m.createVisitBar(site.Visits, sites[0].Visits, 20)
 
+		name := site.Domain
+		if i == m.siteItem {
+			name = headerStyle.Render(selectionMarker + " " + site.Domain)
+			selected = lineOf(&content)
+		}
 		content.WriteString(fmt.Sprintf("%s %s %s\n",
 			highlightStyle.Render(rank),
 			bar,
-			site.domain))
+			name))
 		content.WriteString(fmt.Sprintf("    %s visits • %s entries\n",
-			dimStyle.Render(fmt.Sprintf("%d", site.visits)),
-			dimStyle.Render(fmt.Sprintf("%d", site.count))))
+			dimStyle.Render(fmt.Sprintf("%d", site.Visits)),
+			dimStyle.Render(fmt.Sprintf("%d", site.Entries))))
+		if len(allBrowsers) > 1 {
+			content.WriteString("    " + dimStyle.Render(stats.Breakdown(site.Browsers)) + "\n")
+		}
 		content.WriteString("\n")
 	}
 
-	return cardStyle.Render(content.String())
+	return cardStyle.Render(content.String()), selected
 }
 
-func (m ChromeHistoryModel) renderDetails() string {
-	if len(m.historyData) == 0 {
-		return cardStyle.Render("No detailed data available")
+// Each Details entry takes a title, URL and metadata line plus a blank line.
+const detailsEntryLines = 4
+
+// detailsShown returns how many Details entries fit in the viewport, besides
+// the card's border and padding and the header.
+func (m ChromeHistoryModel) detailsShown() int {
+	return max(1, (m.viewport.Height-7)/detailsEntryLines)
+}
+
+// scrollDetails moves the window of Details entries on screen just enough to
+// show the selected entry.
+func (m *ChromeHistoryModel) scrollDetails() {
+	shown := m.detailsShown()
+	if m.selectedItem < m.detailsTop {
+		m.detailsTop = m.selectedItem
+	} else if m.selectedItem >= m.detailsTop+shown {
+		m.detailsTop = m.selectedItem - shown + 1
 	}
+}
 
-	// Show detailed view of recent entries
-	var content strings.Builder
-	content.WriteString(headerStyle.Render("🔍 Recent History Details") + "\n\n")
+// renderDetails lists the entries on screen, most recent first, and returns
+// the line of the selected one.
+func (m ChromeHistoryModel) renderDetails() (string, int) {
+	if len(m.details) == 0 {
+		return cardStyle.Render("No detailed data available"), -1
+	}
 
-	// Sort by visit time (most recent first)
-	sortedEntries := make([]types.VisitEntry, len(m.historyData))
-	copy(sortedEntries, m.historyData)
-	sort.Slice(sortedEntries, func(i, j int) bool {
-		return sortedEntries[i].VisitTime.After(sortedEntries[j].VisitTime)
-	})
+	first := m.detailsTop
+	last := min(first+m.detailsShown(), len(m.details))
 
-	// Show top 20 recent entries
-	for i, entry := range sortedEntries {
-		if i >= 20 {
-			break
-		}
+	var content strings.Builder
+	content.WriteString(headerStyle.Render("🔍 Recent History Details") +
+		dimStyle.Render(fmt.Sprintf("  %d–%d of %d", first+1, last, len(m.details))) + "\n\n")
 
-		timeStr := entry.VisitTime.Format("Jan 2, 15:04")
+	selected := -1
+	for i := first; i < last; i++ {
+		entry := m.details[i]
+		timeStr := entry.VisitTime.In(m.loc).Format("Jan 2, 15:04")
 		title := truncateString(entry.Title, 60)
 		if title == "" {
 			title = "Untitled"
 		}
 
-		content.WriteString(fmt.Sprintf("🌐 %s\n", highlightStyle.Render(title)))
-		content.WriteString(fmt.Sprintf("   %s\n", dimStyle.Render(entry.URL)))
-		content.WriteString(fmt.Sprintf("   %s • %s visits\n",
+		if i == m.selectedItem {
+			selected = lineOf(&content)
+			content.WriteString(headerStyle.Render(selectionMarker+" "+title) + "\n")
+		} else {
+			content.WriteString(fmt.Sprintf("🌐 %s\n", highlightStyle.Render(title)))
+		}
+		content.WriteString(fmt.Sprintf("   %s\n", dimStyle.Render(m.originalURL(entry))))
+		content.WriteString(fmt.Sprintf("   %s • %s visits • %s\n",
 			dimStyle.Render(timeStr),
-			dimStyle.Render(fmt.Sprintf("%d", entry.VisitCount))))
+			dimStyle.Render(fmt.Sprintf("%d", entry.VisitCount)),
+			dimStyle.Render(entry.Browser)))
 		content.WriteString("\n")
 	}
 
-	return cardStyle.Render(content.String())
+	return cardStyle.Render(content.String()), selected
+}
+
+// updateDetails redraws Details after the selection moved, keeping the
+// selected entry in view.
+func (m *ChromeHistoryModel) updateDetails() {
+	if m.currentView != "details" || m.pane != "" {
+		return
+	}
+	m.updateContent()
+}
+
+// selectedEntry returns the entry selected in Details.
+func (m ChromeHistoryModel) selectedEntry() (types.VisitEntry, bool) {
+	if m.selectedItem < 0 || m.selectedItem >= len(m.details) {
+		return types.VisitEntry{}, false
+	}
+	return m.details[m.selectedItem], true
+}
+
+// newestFirst returns a copy of entries sorted most recent first.
+func newestFirst(entries []types.VisitEntry) []types.VisitEntry {
+	sorted := make([]types.VisitEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].VisitTime.After(sorted[j].VisitTime)
+	})
+	return sorted
 }
 
 // Helper functions
 
+// GroupByDate groups entries by their calendar date (YYYY-MM-DD) in loc and
+// returns the dates in ascending order along with the groups.
+func GroupByDate(entries []types.VisitEntry, loc *time.Location) ([]string, map[string][]types.VisitEntry) {
+	groups := make(map[string][]types.VisitEntry)
+	for _, entry := range entries {
+		date := entry.VisitTime.In(loc).Format("2006-01-02")
+		groups[date] = append(groups[date], entry)
+	}
+
+	dates := make([]string, 0, len(groups))
+	for date := range groups {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates, groups
+}
+
 func (m ChromeHistoryModel) createDomainChart(domains map[string]int) string {
 	// Sort domains by frequency
 	type domainCount struct {
@@ -419,33 +764,9 @@ func (m ChromeHistoryModel) createActivityBar(activity, max int) string {
 	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
 }
 
-func extractDomain(url string) string {
-	// Simple domain extraction
-	if strings.HasPrefix(url, "http://") {
-		url = url[7:]
-	} else if strings.HasPrefix(url, "https://") {
-		url = url[8:]
-	}
-
-	if strings.HasPrefix(url, "www.") {
-		url = url[4:]
-	}
-
-	parts := strings.Split(url, "/")
-	return parts[0]
-}
-
 func truncateString(s string, length int) string {
 	if len(s) <= length {
 		return s
 	}
 	return s[:length-3] + "..."
 }
-
-// RunChromeHistoryViewer starts the Chrome history visualization
-func RunChromeHistoryViewer(historyData []types.VisitEntry) error {
-	m := NewChromeHistoryModel(historyData, 120, 40)
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	_, err := p.Run()
-	return err
-}
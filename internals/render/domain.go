@@ -0,0 +1,189 @@
+// render/domain.go
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// topSitesShown is how many sites Top Sites lists.
+const topSitesShown = 15
+
+// sparkRamp draws bars of increasing height in a single line.
+var sparkRamp = []rune("▁▂▃▄▅▆▇█")
+
+// updateSites handles the Top Sites keys: ↑/↓ pick a site, h switches between
+// grouping by host and by registrable domain, enter opens the site's page and
+// esc goes back. It reports whether the key was used.
+func (m *ChromeHistoryModel) updateSites(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "up", "k":
+		if m.domain != "" {
+			return false // let the viewport scroll the domain page
+		}
+		if m.siteItem > 0 {
+			m.siteItem--
+		}
+	case "down", "j":
+		if m.domain != "" {
+			return false
+		}
+		if m.siteItem < min(len(stats.TopSitesBy(m.historyData, m.grouping)), topSitesShown)-1 {
+			m.siteItem++
+		}
+	case "h":
+		if m.domain != "" {
+			return false
+		}
+		if m.grouping == stats.ByHost {
+			m.grouping = stats.ByDomain
+		} else {
+			m.grouping = stats.ByHost
+		}
+		m.siteItem = 0
+	case "enter":
+		sites := stats.TopSitesBy(m.historyData, m.grouping)
+		if m.domain != "" || m.siteItem >= len(sites) {
+			return false
+		}
+		m.domain = sites[m.siteItem].Domain
+		m.updateContent()
+		m.viewport.GotoTop()
+		return true
+	case "esc", "backspace":
+		if m.domain == "" {
+			return false
+		}
+		m.domain = ""
+	default:
+		return false
+	}
+	m.updateContent()
+	return true
+}
+
+// renderDomain shows the visits to m.domain: when and how often it is visited,
+// its most visited paths and the domains that led to it.
+func (m ChromeHistoryModel) renderDomain() string {
+	d := stats.DomainSummary(m.historyData, m.domain, m.grouping, m.loc)
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render("🌐 "+m.domain) + "\n\n")
+	if d.Visits == 0 {
+		content.WriteString(dimStyle.Render("No visits in the selected range") + "\n")
+		return cardStyle.Render(content.String())
+	}
+
+	content.WriteString(fmt.Sprintf("📊 %s visits on %s of %s days • %s per day\n",
+		highlightStyle.Render(fmt.Sprintf("%d", d.Visits)),
+		highlightStyle.Render(fmt.Sprintf("%d", len(d.Daily))),
+		highlightStyle.Render(fmt.Sprintf("%d", d.Days)),
+		highlightStyle.Render(fmt.Sprintf("%.1f", d.PerDay()))))
+	content.WriteString(fmt.Sprintf("📅 First seen %s • last seen %s\n\n",
+		dimStyle.Render(d.FirstVisit.In(m.loc).Format("Jan 2, 2006 15:04")),
+		dimStyle.Render(d.LastVisit.In(m.loc).Format("Jan 2, 2006 15:04"))))
+
+	// Timeline of the 10 most recent days with visits
+	content.WriteString(headerStyle.Render("📅 Timeline") + "\n\n")
+	dates := make([]string, 0, len(d.Daily))
+	busiest := 0
+	for date, count := range d.Daily {
+		dates = append(dates, date)
+		busiest = max(busiest, count)
+	}
+	sort.Strings(dates)
+	if len(dates) > 10 {
+		dates = dates[len(dates)-10:]
+	}
+	for _, date := range dates {
+		content.WriteString(fmt.Sprintf("%s %s %d\n", dimStyle.Render(date), m.createActivityBar(d.Daily[date], busiest), d.Daily[date]))
+	}
+
+	content.WriteString("\n" + headerStyle.Render("🕒 Time of Day") + "\n\n")
+	content.WriteString(hourSparkline(d.Hours) + "\n")
+	content.WriteString(dimStyle.Render("0     6     12    18   23") + "\n\n")
+
+	content.WriteString(headerStyle.Render("🔗 Top Paths") + "\n\n")
+	for i, path := range d.Paths {
+		if i >= 10 {
+			break
+		}
+		line := fmt.Sprintf("%s %s", m.createVisitBar(path.Visits, d.Paths[0].Visits, 10), truncateString(path.Path, 50))
+		if path.Title != "" {
+			line += " " + dimStyle.Render(truncateString(path.Title, 40))
+		}
+		content.WriteString(fmt.Sprintf("%s %s\n", line, dimStyle.Render(fmt.Sprintf("(%d)", path.Visits))))
+	}
+
+	content.WriteString("\n" + headerStyle.Render("🧭 Referred By") + "\n\n")
+	referrers := m.referringDomains()
+	if len(referrers) == 0 {
+		content.WriteString(dimStyle.Render("No other site is known to have linked here") + "\n")
+	}
+	for i, r := range referrers {
+		if i >= 10 {
+			break
+		}
+		content.WriteString(fmt.Sprintf("%s %s %s\n", m.createVisitBar(r.count, referrers[0].count, 10), r.domain, dimStyle.Render(fmt.Sprintf("(%d)", r.count))))
+	}
+
+	return cardStyle.Render(content.String())
+}
+
+type referrerCount struct {
+	domain string
+	count  int
+}
+
+// referringDomains counts the other domains whose links led to visits of
+// m.domain, most frequent first.
+func (m ChromeHistoryModel) referringDomains() []referrerCount {
+	counts := make(map[string]int)
+	for _, entry := range m.historyData {
+		if stats.SiteOf(entry.URL, m.grouping) != m.domain {
+			continue
+		}
+		from, ok := m.graph.Referrer(entry)
+		if !ok {
+			continue
+		}
+		if domain := stats.SiteOf(from.URL, m.grouping); domain != m.domain {
+			counts[domain]++
+		}
+	}
+
+	referrers := make([]referrerCount, 0, len(counts))
+	for domain, count := range counts {
+		referrers = append(referrers, referrerCount{domain, count})
+	}
+	sort.Slice(referrers, func(i, j int) bool {
+		if referrers[i].count != referrers[j].count {
+			return referrers[i].count > referrers[j].count
+		}
+		return referrers[i].domain < referrers[j].domain
+	})
+	return referrers
+}
+
+// hourSparkline draws one bar per hour, scaled to the busiest hour.
+func hourSparkline(hours [24]int) string {
+	peak := 0
+	for _, count := range hours {
+		peak = max(peak, count)
+	}
+
+	var line strings.Builder
+	for _, count := range hours {
+		if count == 0 {
+			line.WriteString(dimStyle.Render("·"))
+			continue
+		}
+		level := (count*len(sparkRamp) - 1) / peak
+		line.WriteString(highlightStyle.Render(string(sparkRamp[level])))
+	}
+	return line.String()
+}
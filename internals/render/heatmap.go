@@ -0,0 +1,105 @@
+// render/heatmap.go
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// heatmapDomains is how many of the top domains the heatmap can be filtered by.
+const heatmapDomains = 10
+
+// heatRamp goes from no visits to the busiest hour.
+var heatRamp = []lipgloss.Color{"#2B2B2B", "#3C2A6B", "#5B3BB0", "#7D56F4", "#B36CF6", "#EE6FF8"}
+
+// weekdays are the heatmap rows, starting on Monday.
+var weekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// heatmapDomainList returns the domains the heatmap can be filtered by; the
+// first entry, "", shows every domain.
+func (m ChromeHistoryModel) heatmapDomainList() []string {
+	domains := []string{""}
+	for i, site := range stats.TopSites(m.historyData) {
+		if i >= heatmapDomains {
+			break
+		}
+		domains = append(domains, site.Domain)
+	}
+	return domains
+}
+
+// cycleHeatmapDomain selects the next (or, with step -1, previous) domain.
+func (m *ChromeHistoryModel) cycleHeatmapDomain(step int) {
+	domains := m.heatmapDomainList()
+	m.heatmapDomain = (m.heatmapDomain + step + len(domains)) % len(domains)
+	m.updateContent()
+}
+
+func (m ChromeHistoryModel) renderHeatmap() string {
+	if len(m.historyData) == 0 {
+		return cardStyle.Render("No activity data available")
+	}
+
+	domains := m.heatmapDomainList()
+	domain := ""
+	if m.heatmapDomain < len(domains) {
+		domain = domains[m.heatmapDomain]
+	}
+	heat := stats.ActivityHeatmap(m.historyData, m.loc, domain)
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render("🔥 Activity Heatmap") + "\n\n")
+	if domain == "" {
+		content.WriteString("🌐 All domains " + dimStyle.Render("(d/D to filter by a top domain)") + "\n\n")
+	} else {
+		content.WriteString("🌐 " + highlightStyle.Render(domain) + dimStyle.Render(fmt.Sprintf(" (%d/%d, d/D to change)", m.heatmapDomain, len(domains)-1)) + "\n\n")
+	}
+
+	// Hour header, labelled every three hours
+	content.WriteString("     ")
+	for hour := 0; hour < 24; hour += 3 {
+		content.WriteString(fmt.Sprintf("%-6s", fmt.Sprintf("%02d", hour)))
+	}
+	content.WriteString("\n")
+
+	peak, peakDay, peakHour := heat.Max()
+	for _, day := range weekdays {
+		content.WriteString(fmt.Sprintf("%-4s ", day.String()[:3]))
+		for hour := 0; hour < 24; hour++ {
+			content.WriteString(heatCell(heat[day][hour], peak))
+		}
+		content.WriteString("\n")
+	}
+
+	// Legend
+	content.WriteString("\n     " + dimStyle.Render("less "))
+	for i := range heatRamp {
+		content.WriteString(lipgloss.NewStyle().Background(heatRamp[i]).Render("  "))
+	}
+	content.WriteString(dimStyle.Render(" more") + "\n\n")
+
+	content.WriteString(fmt.Sprintf("📊 %d visits", heat.Total()))
+	if peak > 0 {
+		content.WriteString(fmt.Sprintf(" • busiest: %s %02d:00 (%d visits)", peakDay.String()[:3], peakHour, peak))
+	}
+	content.WriteString("\n")
+
+	return cardStyle.Render(content.String())
+}
+
+// heatCell renders one hour as a coloured block; count picks the shade
+// relative to the busiest hour.
+func heatCell(count, peak int) string {
+	level := 0
+	if count > 0 && peak > 0 {
+		// Levels 1 to len-1 for any visits, so a single visit still shows
+		level = 1 + (count*(len(heatRamp)-1)-1)/peak
+	}
+	return lipgloss.NewStyle().Background(heatRamp[level]).Render("  ")
+}
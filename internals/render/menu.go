@@ -3,7 +3,9 @@ package render
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
 	"github.com/charmbracelet/bubbles/viewport"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -19,23 +21,59 @@ func (i browserItem) Title() string       { return i.name }
 func (i browserItem) Description() string { return i.desc }
 func (i browserItem) FilterValue() string { return i.name }
 
+type profileItem struct {
+	profile parse.Profile
+	checked bool
+}
+
+func (i profileItem) Title() string {
+	box := "[ ]"
+	if i.checked {
+		box = "[x]"
+	}
+	return box + " " + i.profile.Name
+}
+func (i profileItem) Description() string { return i.profile.Path }
+func (i profileItem) FilterValue() string { return i.profile.Name }
+
+// BrowserChoice is what the user picked in the browser menu.
+type BrowserChoice struct {
+	Browser string
+	// Profiles are the profiles to read. Empty means the browser's default profile.
+	Profiles []parse.Profile
+}
+
 type model struct {
 	list     list.Model
-	selected string
-	done     bool
-	viewport viewport.Model
+	browsers list.Model
+	// choosingProfile is set once a browser with several profiles was picked.
+	choosingProfile bool
+	selected        BrowserChoice
+	viewport        viewport.Model
 }
 
 func NewModel() model {
-	items := []list.Item{
-		browserItem{name: "Chrome", desc: "Google Chrome history file"},
-		browserItem{name: "Firefox", desc: "Mozilla Firefox history file"},
+	// Only offer browsers whose history file was found. If none were, list every
+	// source so the user still gets an error explaining where we looked.
+	title := "Choose your browser"
+	sources := parse.DetectedSources()
+	if len(sources) == 0 {
+		title = "No browser history detected, choose a browser to troubleshoot"
+		sources = parse.Sources()
+	}
+
+	var items []list.Item
+	if len(sources) > 1 && title == "Choose your browser" {
+		items = append(items, browserItem{name: parse.AllBrowsers, desc: "Every detected browser and profile, merged"})
+	}
+	for _, src := range sources {
+		items = append(items, browserItem{name: src.Name(), desc: src.Description()})
 	}
 	l := list.New(items, list.NewDefaultDelegate(), 120, 40)
-	l.Title = "Choose your browser"
+	l.Title = title
 	vp := viewport.New(40, 20)
 	vp.SetContent(l.View())
-	return model{list: l, viewport: vp}
+	return model{list: l, browsers: l, viewport: vp}
 }
 
 func (m model) Init() tea.Cmd {
@@ -43,14 +81,46 @@ func (m model) Init() tea.Cmd {
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return m.update(msg)
+}
+
+// update handles the menu keys. Picking a browser, and its profiles if it has
+// several, sends a browserChosenMsg.
+func (m model) update(msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
 		switch msg.String() {
 		case "enter":
-			item := m.list.SelectedItem().(browserItem)
-			m.selected = item.name
-			m.done = true
-			return m, tea.Quit
+			if m.choosingProfile {
+				m.selected.Profiles = m.checkedProfiles()
+				return m, m.choose()
+			}
+			item, ok := m.list.SelectedItem().(browserItem)
+			if !ok {
+				// The filter matches no browser.
+				return m, nil
+			}
+			m.selected = BrowserChoice{Browser: item.name}
+			if m.showProfiles(item.name) {
+				return m, nil
+			}
+			return m, m.choose()
+		case " ":
+			if m.choosingProfile {
+				if item, ok := m.list.SelectedItem().(profileItem); ok {
+					item.checked = !item.checked
+					m.list.SetItem(m.list.Index(), item)
+				}
+				return m, nil
+			}
+		case "esc", "backspace":
+			if m.choosingProfile {
+				m.reset()
+				return m, nil
+			}
 		case "q", "ctrl+c":
 			return m, tea.Quit
 		}
@@ -61,20 +131,73 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m model) View() string {
-	if m.done {
-		return cardStyle.Render(fmt.Sprintf("You chose: %s", m.selected))
+// choose reports the selected browser and profiles.
+func (m model) choose() tea.Cmd {
+	choice := m.selected
+	return func() tea.Msg { return browserChosenMsg(choice) }
+}
+
+// reset leaves the profile step, if shown, for the browser list.
+func (m *model) reset() {
+	if m.choosingProfile {
+		m.choosingProfile = false
+		m.list = m.browsers
+	}
+}
+
+// showProfiles switches to the profile step when the browser has more than one
+// profile. It reports whether it did.
+func (m *model) showProfiles(browser string) bool {
+	src, ok := parse.Lookup(browser)
+	if !ok {
+		return false
+	}
+	profiles, err := src.Profiles()
+	if err != nil || len(profiles) < 2 {
+		return false
+	}
+
+	var items []list.Item
+	for _, p := range profiles {
+		items = append(items, profileItem{profile: p})
+	}
+	m.browsers = m.list
+	l := list.New(items, list.NewDefaultDelegate(), m.list.Width(), m.list.Height())
+	l.Title = fmt.Sprintf("Choose %s profiles (space to select, enter to confirm, esc to go back)", browser)
+	m.list = l
+	m.choosingProfile = true
+	return true
+}
+
+// checkedProfiles returns the profiles ticked with space, or the highlighted one
+// if none were ticked.
+func (m model) checkedProfiles() []parse.Profile {
+	var profiles []parse.Profile
+	for _, item := range m.list.Items() {
+		if p := item.(profileItem); p.checked {
+			profiles = append(profiles, p.profile)
+		}
+	}
+	if len(profiles) == 0 {
+		if p, ok := m.list.SelectedItem().(profileItem); ok {
+			profiles = append(profiles, p.profile)
+		}
 	}
+	return profiles
+}
 
+func (m model) View() string {
 	// wrap the list view in the card style
 	return cardStyle.Render(m.list.View())
 }
 
-func GetUserBrowserChoice() (string, error) {
-	prog := tea.NewProgram(NewModel())
-	finalModel, err := prog.Run()
-	if err != nil {
-		return "", err
+func (c BrowserChoice) String() string {
+	if len(c.Profiles) == 0 {
+		return c.Browser
+	}
+	names := make([]string, len(c.Profiles))
+	for i, p := range c.Profiles {
+		names[i] = p.Name
 	}
-	return finalModel.(model).selected, nil
+	return fmt.Sprintf("%s (%s)", c.Browser, strings.Join(names, ", "))
 }
@@ -0,0 +1,159 @@
+// render/sessions.go
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selectionMarker prefixes the selected row.
+const selectionMarker = "▶"
+
+// sessionsView is the state of the Sessions view.
+type sessionsView struct {
+	gap      time.Duration
+	sessions []stats.Session // newest first
+	cursor   int             // selected session
+	open     int             // session shown with its visits, or -1 for the list
+}
+
+// build splits entries into sessions and resets the selection.
+func (v *sessionsView) build(entries []types.VisitEntry) {
+	sessions := stats.Sessions(entries, v.gap)
+	// Show the most recent session first
+	for i, j := 0, len(sessions)-1; i < j; i, j = i+1, j-1 {
+		sessions[i], sessions[j] = sessions[j], sessions[i]
+	}
+	v.sessions = sessions
+	v.cursor = 0
+	v.open = -1
+}
+
+// updateSessions handles the Sessions view's own keys and reports whether it
+// consumed msg.
+func (m *ChromeHistoryModel) updateSessions(msg tea.KeyMsg) bool {
+	v := &m.sessions
+	switch msg.String() {
+	case "up", "k":
+		if v.open >= 0 {
+			return false // let the viewport scroll the session's visits
+		}
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down", "j":
+		if v.open >= 0 {
+			return false
+		}
+		if v.cursor < len(v.sessions)-1 {
+			v.cursor++
+		}
+	case "enter":
+		if v.open >= 0 || len(v.sessions) == 0 {
+			return false
+		}
+		v.open = v.cursor
+		m.viewport.GotoTop()
+	case "esc", "backspace":
+		if v.open < 0 {
+			return false
+		}
+		v.open = -1
+	default:
+		return false
+	}
+	m.updateContent()
+	return true
+}
+
+// renderSessions lists the sessions, or the open one's visits, and returns the
+// line of the selected session in the list.
+func (m ChromeHistoryModel) renderSessions() (string, int) {
+	v := m.sessions
+	if len(v.sessions) == 0 {
+		return cardStyle.Render("No sessions found"), -1
+	}
+	if v.open >= 0 {
+		return m.renderSession(v.sessions[v.open]), -1
+	}
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render(fmt.Sprintf("🧭 Sessions (%d)", len(v.sessions))) + "\n\n")
+
+	selected := -1
+	for i, s := range v.sessions {
+		line := fmt.Sprintf("%s • %s • %d pages",
+			s.Start.In(m.loc).Format("Mon Jan 2, 15:04"), formatDuration(s.Duration()), len(s.Visits))
+		if i == v.cursor {
+			selected = lineOf(&content)
+			content.WriteString(highlightStyle.Render(selectionMarker+" "+line) + "\n")
+		} else {
+			content.WriteString("  " + line + "\n")
+		}
+		content.WriteString("    " + dimStyle.Render(summarizeDomains(s.Domains(), 4)) + "\n\n")
+	}
+
+	return cardStyle.Render(content.String()), selected
+}
+
+// renderSession shows one session's visits, oldest first.
+func (m ChromeHistoryModel) renderSession(s stats.Session) string {
+	var content strings.Builder
+	start := s.Start.In(m.loc)
+	content.WriteString(headerStyle.Render(fmt.Sprintf("🧭 Session %s – %s",
+		start.Format("Mon Jan 2, 15:04"), s.End.In(m.loc).Format("15:04"))) + "\n\n")
+	content.WriteString(fmt.Sprintf("⏱  %s • %d pages\n", formatDuration(s.Duration()), len(s.Visits)))
+	content.WriteString(fmt.Sprintf("🌐 %s\n\n", summarizeDomains(s.Domains(), 8)))
+
+	for _, entry := range s.Visits {
+		title := truncateString(entry.Title, 60)
+		if title == "" {
+			title = "Untitled"
+		}
+		content.WriteString(fmt.Sprintf("%s %s\n", dimStyle.Render(entry.VisitTime.In(m.loc).Format("15:04")), highlightStyle.Render(title)))
+		content.WriteString(fmt.Sprintf("      %s\n", dimStyle.Render(truncateString(m.originalURL(entry), 80))))
+	}
+
+	return cardStyle.Render(content.String())
+}
+
+// lineOf returns the line of a card that content's next line will be on once
+// it is rendered with cardStyle.
+func lineOf(content *strings.Builder) int {
+	return strings.Count(content.String(), "\n") + cardStyle.GetBorderTopSize() + cardStyle.GetPaddingTop()
+}
+
+// scrollToLine scrolls the viewport so the given line of its content is visible.
+func (m *ChromeHistoryModel) scrollToLine(line int) {
+	if line < m.viewport.YOffset {
+		m.viewport.SetYOffset(line)
+	} else if line >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(line - m.viewport.Height + 3) // keep the row's details in view too
+	}
+}
+
+// summarizeDomains lists up to n domains and how many more there are.
+func summarizeDomains(domains []string, n int) string {
+	if len(domains) <= n {
+		return strings.Join(domains, ", ")
+	}
+	return fmt.Sprintf("%s +%d more", strings.Join(domains[:n], ", "), len(domains)-n)
+}
+
+// formatDuration formats d in hours and minutes, e.g. "1h 5m".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
@@ -0,0 +1,78 @@
+// render/trail.go
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+// renderTrail shows how the selected entry was reached, as a tree from the
+// first visit of its referrer chain, and the pages opened from it. It returns
+// the line of the selected entry.
+func (m ChromeHistoryModel) renderTrail() (string, int) {
+	selected, ok := m.selectedEntry()
+	if !ok {
+		return cardStyle.Render("No entry selected"), -1
+	}
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render("🧭 How did I get here?") + "\n\n")
+
+	chain := m.graph.Chain(selected)
+	if len(chain) == 1 {
+		how := "directly"
+		if selected.Transition != types.TransitionUnknown && selected.Transition != types.TransitionLink {
+			how = "by " + strings.ReplaceAll(string(selected.Transition), "_", " ")
+		}
+		content.WriteString(dimStyle.Render("No referring page is known, it was opened "+how+".") + "\n\n")
+	}
+
+	indent, here := "", -1
+	for i, entry := range chain {
+		branch := ""
+		if i > 0 {
+			branch = "└─ "
+		}
+		last := i == len(chain)-1
+		if last {
+			here = lineOf(&content)
+		}
+		content.WriteString(m.trailNode(entry, indent+branch, indent+strings.Repeat(" ", len([]rune(branch))), last))
+		if i > 0 {
+			indent += "   "
+		}
+	}
+
+	next := m.graph.Next(selected)
+	for i, entry := range next {
+		branch, cont := "├─ ", "│  "
+		if i == len(next)-1 {
+			branch, cont = "└─ ", "   "
+		}
+		content.WriteString(m.trailNode(entry, indent+branch, indent+cont, false))
+	}
+
+	return cardStyle.Render(content.String()), here
+}
+
+// trailNode renders one visit of the trail: its title after prefix, then its
+// URL and time after indent.
+func (m ChromeHistoryModel) trailNode(entry types.VisitEntry, prefix, indent string, here bool) string {
+	title := truncateString(entry.Title, 60)
+	if title == "" {
+		title = "Untitled"
+	}
+	line := dimStyle.Render(prefix) + highlightStyle.Render(title)
+	if here {
+		line = dimStyle.Render(prefix) + headerStyle.Render(selectionMarker+" "+title) + dimStyle.Render("  ◀ you are here")
+	}
+
+	details := entry.VisitTime.In(m.loc).Format("Jan 2, 15:04")
+	if entry.Transition != types.TransitionUnknown {
+		details += " • " + strings.ReplaceAll(string(entry.Transition), "_", " ")
+	}
+	return fmt.Sprintf("%s\n%s\n", line,
+		dimStyle.Render(indent+"   "+truncateString(m.originalURL(entry), 70)+" • "+details))
+}
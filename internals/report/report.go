@@ -0,0 +1,253 @@
+// Package report renders history statistics into a single self-contained HTML
+// file. Charts are inline SVG and scripts are inline, so the report opens
+// offline and can be attached anywhere.
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+//go:embed report.html.tmpl
+var reportTemplate string
+
+var tmpl = template.Must(template.New("report").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(reportTemplate))
+
+const (
+	chartWidth  = 900.0
+	chartHeight = 220.0
+	// How many rows the Top Sites and Details sections show
+	topSitesShown = 15
+	detailsShown  = 500
+)
+
+// Options configures a report.
+type Options struct {
+	Title    string
+	Location *time.Location // time zone to display and group visits in; nil means local time
+	Now      time.Time      // generation time shown in the footer; zero means time.Now
+}
+
+// bar is one bar of a chart, in SVG user units.
+type bar struct {
+	X, Y, W, H float64
+	Label      string
+	Tip        string
+}
+
+// chart is a vertical bar chart with labels under some of the bars.
+type chart struct {
+	Width, Height float64
+	Bars          []bar
+	Labels        []bar // only X and Label are used
+	Max           int
+}
+
+// siteRow is a Top Sites row with its bar width as a percentage.
+type siteRow struct {
+	stats.Site
+	Percent   float64
+	Breakdown string
+}
+
+// detailRow is a row of the Details table.
+type detailRow struct {
+	Time, Title, URL, Domain, Browser string
+	Visits                            int
+}
+
+type reportData struct {
+	Title     string
+	Generated string
+	Summary   stats.Summary
+	First     string
+	Last      string
+	Browsers  []siteRow
+	Timeline  chart
+	Hours     chart
+	Sites     []siteRow
+	Details   []detailRow
+	Truncated int // entries left out of Details
+}
+
+// Write renders the report for entries to w.
+func Write(w io.Writer, entries []types.VisitEntry, opts Options) error {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	title := opts.Title
+	if title == "" {
+		title = "Histograph report"
+	}
+
+	data := reportData{
+		Title:     title,
+		Generated: now.In(loc).Format("2006-01-02 15:04 MST"),
+		Summary:   stats.Summarize(entries),
+		Timeline:  timelineChart(entries, loc),
+		Hours:     hourChart(entries, loc),
+		Sites:     siteRows(stats.TopSites(entries), topSitesShown),
+		Browsers:  browserRows(entries),
+	}
+	if len(entries) > 0 {
+		data.First = data.Summary.FirstVisit.In(loc).Format("2006-01-02 15:04")
+		data.Last = data.Summary.LastVisit.In(loc).Format("2006-01-02 15:04")
+	}
+
+	sorted := make([]types.VisitEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].VisitTime.After(sorted[j].VisitTime)
+	})
+	for i, entry := range sorted {
+		if i >= detailsShown {
+			data.Truncated = len(sorted) - detailsShown
+			break
+		}
+		data.Details = append(data.Details, detailRow{
+			Time:    entry.VisitTime.In(loc).Format("2006-01-02 15:04"),
+			Title:   entry.Title,
+			URL:     entry.URL,
+			Domain:  stats.Domain(entry.URL),
+			Browser: entry.Browser,
+			Visits:  entry.VisitCount,
+		})
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// timelineChart charts visits per day in loc, including days without visits.
+func timelineChart(entries []types.VisitEntry, loc *time.Location) chart {
+	if len(entries) == 0 {
+		return chart{Width: chartWidth, Height: chartHeight}
+	}
+
+	counts := make(map[string]int)
+	var first, last time.Time
+	for _, entry := range entries {
+		t := entry.VisitTime.In(loc)
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		counts[day.Format("2006-01-02")]++
+		if first.IsZero() || day.Before(first) {
+			first = day
+		}
+		if day.After(last) {
+			last = day
+		}
+	}
+
+	var labels []string
+	var values []int
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		labels = append(labels, date)
+		values = append(values, counts[date])
+	}
+	return barChart(labels, values, "visits")
+}
+
+// hourChart charts visits per hour of the day in loc.
+func hourChart(entries []types.VisitEntry, loc *time.Location) chart {
+	values := make([]int, 24)
+	labels := make([]string, 24)
+	for h := range labels {
+		labels[h] = fmt.Sprintf("%02d:00", h)
+	}
+	for _, entry := range entries {
+		values[entry.VisitTime.In(loc).Hour()]++
+	}
+	return barChart(labels, values, "visits")
+}
+
+// barChart lays out one bar per value. Around a dozen labels are shown under
+// the bars however many there are.
+func barChart(labels []string, values []int, unit string) chart {
+	c := chart{Width: chartWidth, Height: chartHeight}
+	for _, v := range values {
+		c.Max = max(c.Max, v)
+	}
+	if len(values) == 0 {
+		return c
+	}
+
+	slot := chartWidth / float64(len(values))
+	gap := min(slot*0.2, 2)
+	every := max(1, (len(values)+11)/12)
+	for i, v := range values {
+		h := 0.0
+		if c.Max > 0 {
+			h = float64(v) / float64(c.Max) * chartHeight
+		}
+		b := bar{
+			X:     float64(i) * slot,
+			Y:     chartHeight - h,
+			W:     slot - gap,
+			H:     h,
+			Label: labels[i],
+			Tip:   fmt.Sprintf("%s: %d %s", labels[i], v, unit),
+		}
+		c.Bars = append(c.Bars, b)
+		if i%every == 0 {
+			c.Labels = append(c.Labels, bar{X: b.X + b.W/2, Label: labels[i]})
+		}
+	}
+	return c
+}
+
+func siteRows(sites []stats.Site, n int) []siteRow {
+	if len(sites) > n {
+		sites = sites[:n]
+	}
+	rows := make([]siteRow, len(sites))
+	for i, site := range sites {
+		rows[i] = siteRow{Site: site}
+		if len(site.Browsers) > 1 {
+			rows[i].Breakdown = stats.Breakdown(site.Browsers)
+		}
+		if sites[0].Visits > 0 {
+			rows[i].Percent = float64(site.Visits) / float64(sites[0].Visits) * 100
+		}
+	}
+	return rows
+}
+
+// browserRows counts entries per browser, or returns nil for a single browser.
+func browserRows(entries []types.VisitEntry) []siteRow {
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		counts[entry.Browser]++
+	}
+	if len(counts) < 2 {
+		return nil
+	}
+
+	var sites []stats.Site
+	for name, count := range counts {
+		sites = append(sites, stats.Site{Domain: name, Visits: count, Entries: count})
+	}
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i].Visits != sites[j].Visits {
+			return sites[i].Visits > sites[j].Visits
+		}
+		return sites[i].Domain < sites[j].Domain
+	})
+	return siteRows(sites, len(sites))
+}
@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+// DomainStats summarizes the visits to one domain. Every entry counts as one
+// visit, as in the timeline and heatmap.
+type DomainStats struct {
+	Domain     string
+	Visits     int
+	FirstVisit time.Time
+	LastVisit  time.Time
+	Days       int            // calendar days from the first to the last visit
+	Daily      map[string]int // visits per date (YYYY-MM-DD)
+	Hours      [24]int        // visits per hour of the day
+	Paths      []Path         // most visited first
+}
+
+// Path counts the visits to one path of a domain.
+type Path struct {
+	Path   string
+	Title  string // title of the most recent visit
+	Visits int
+}
+
+// DomainSummary summarizes the entries of domain, a site grouped by g, with
+// days and hours in loc.
+func DomainSummary(entries []types.VisitEntry, domain string, g Grouping, loc *time.Location) DomainStats {
+	d := DomainStats{Domain: domain, Daily: make(map[string]int)}
+	index := make(map[string]int)
+	latest := make(map[string]time.Time)
+
+	for _, entry := range entries {
+		if SiteOf(entry.URL, g) != domain {
+			continue
+		}
+		d.Visits++
+		if d.FirstVisit.IsZero() || entry.VisitTime.Before(d.FirstVisit) {
+			d.FirstVisit = entry.VisitTime
+		}
+		if entry.VisitTime.After(d.LastVisit) {
+			d.LastVisit = entry.VisitTime
+		}
+		t := entry.VisitTime.In(loc)
+		d.Daily[t.Format("2006-01-02")]++
+		d.Hours[t.Hour()]++
+
+		path := urlPath(entry.URL)
+		i, ok := index[path]
+		if !ok {
+			i = len(d.Paths)
+			index[path] = i
+			d.Paths = append(d.Paths, Path{Path: path})
+		}
+		d.Paths[i].Visits++
+		if entry.Title != "" && !entry.VisitTime.Before(latest[path]) {
+			d.Paths[i].Title = entry.Title
+			latest[path] = entry.VisitTime
+		}
+	}
+
+	if d.Visits > 0 {
+		first, last := d.FirstVisit.In(loc), d.LastVisit.In(loc)
+		start := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+		end := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, loc)
+		// Count calendar days rather than 24h periods, which DST would skew
+		for t := start; !t.After(end); t = t.AddDate(0, 0, 1) {
+			d.Days++
+		}
+	}
+
+	sort.SliceStable(d.Paths, func(i, j int) bool {
+		if d.Paths[i].Visits != d.Paths[j].Visits {
+			return d.Paths[i].Visits > d.Paths[j].Visits
+		}
+		return d.Paths[i].Path < d.Paths[j].Path
+	})
+	return d
+}
+
+// PerDay returns the average number of visits per day from the first to the
+// last visit.
+func (d DomainStats) PerDay() float64 {
+	if d.Days == 0 {
+		return 0
+	}
+	return float64(d.Visits) / float64(d.Days)
+}
+
+// urlPath returns the path of rawURL without query or fragment, "/" for the
+// root.
+func urlPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
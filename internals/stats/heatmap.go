@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+// Heatmap counts visits per day of the week and hour of the day, indexed by
+// time.Weekday (Sunday is 0) and hour.
+type Heatmap [7][24]int
+
+// ActivityHeatmap counts entries by weekday and hour in loc. With a domain,
+// only entries of that domain are counted.
+func ActivityHeatmap(entries []types.VisitEntry, loc *time.Location, domain string) Heatmap {
+	var h Heatmap
+	for _, entry := range entries {
+		if domain != "" && Domain(entry.URL) != domain {
+			continue
+		}
+		t := entry.VisitTime.In(loc)
+		h[t.Weekday()][t.Hour()]++
+	}
+	return h
+}
+
+// Max returns the highest count and where it is.
+func (h Heatmap) Max() (count int, day time.Weekday, hour int) {
+	for d := range h {
+		for hr, c := range h[d] {
+			if c > count {
+				count, day, hour = c, time.Weekday(d), hr
+			}
+		}
+	}
+	return count, day, hour
+}
+
+// Total returns the sum of all counts.
+func (h Heatmap) Total() int {
+	total := 0
+	for d := range h {
+		for _, c := range h[d] {
+			total += c
+		}
+	}
+	return total
+}
@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+// DefaultSessionGap is the inactivity after which a new session starts.
+const DefaultSessionGap = 30 * time.Minute
+
+// Session is a burst of browsing: visits without a pause longer than the
+// session gap, plus the visits that followed links from them.
+type Session struct {
+	Start  time.Time
+	End    time.Time          // time of the last visit
+	Visits []types.VisitEntry // oldest first
+}
+
+// Duration is the time from the first to the last visit of the session.
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Domains returns the domains visited in the session, most visited first.
+// Ties keep the order in which the domains were first visited.
+func (s Session) Domains() []string {
+	counts := make(map[string]int)
+	var domains []string
+	for _, visit := range s.Visits {
+		domain := Domain(visit.URL)
+		if counts[domain] == 0 {
+			domains = append(domains, domain)
+		}
+		counts[domain]++
+	}
+	sort.SliceStable(domains, func(i, j int) bool {
+		return counts[domains[i]] > counts[domains[j]]
+	})
+	return domains
+}
+
+// Sessions splits entries into sessions, oldest first. A visit starts a new
+// session when more than gap has passed since the previous visit, unless it
+// followed a link from a visit of the latest session: reading one page for a
+// long time and then clicking a link on it is still the same session. A gap
+// of zero or less means DefaultSessionGap.
+func Sessions(entries []types.VisitEntry, gap time.Duration) []Session {
+	if gap <= 0 {
+		gap = DefaultSessionGap
+	}
+
+	sorted := make([]types.VisitEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].VisitTime.Before(sorted[j].VisitTime)
+	})
+
+	var sessions []Session
+	inLatest := make(map[types.VisitKey]bool) // visits of the latest session
+	for _, entry := range sorted {
+		referrer := entry.ReferrerKey()
+		n := len(sessions)
+		continues := n > 0 &&
+			(entry.VisitTime.Sub(sessions[n-1].End) <= gap ||
+				entry.ReferrerVisitID != 0 && inLatest[referrer])
+
+		if !continues {
+			sessions = append(sessions, Session{Start: entry.VisitTime})
+			inLatest = make(map[types.VisitKey]bool)
+			n++
+		}
+		s := &sessions[n-1]
+		s.Visits = append(s.Visits, entry)
+		s.End = entry.VisitTime
+		inLatest[entry.Key()] = true
+	}
+	return sessions
+}
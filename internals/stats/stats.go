@@ -0,0 +1,146 @@
+// Package stats computes the history statistics shared by the TUI views and
+// the command line.
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	"github.com/akshatsrivastava11/Histograph/internals/urlnorm"
+)
+
+// Summary holds the headline numbers for a set of entries.
+type Summary struct {
+	Entries    int            `json:"entries"`
+	Visits     int            `json:"visits"`
+	UniqueURLs int            `json:"unique_urls"`
+	Domains    int            `json:"domains"`
+	FirstVisit time.Time      `json:"first_visit"`
+	LastVisit  time.Time      `json:"last_visit"`
+	Browsers   map[string]int `json:"browsers"` // entries per browser
+}
+
+// Summarize computes the Summary of entries.
+func Summarize(entries []types.VisitEntry) Summary {
+	s := Summary{Entries: len(entries), Browsers: make(map[string]int)}
+	urls := make(map[string]bool)
+	domains := make(map[string]bool)
+
+	for _, entry := range entries {
+		s.Visits += entry.VisitCount
+		urls[entry.URL] = true
+		domains[Domain(entry.URL)] = true
+		s.Browsers[entry.Browser]++
+
+		if s.FirstVisit.IsZero() || entry.VisitTime.Before(s.FirstVisit) {
+			s.FirstVisit = entry.VisitTime
+		}
+		if entry.VisitTime.After(s.LastVisit) {
+			s.LastVisit = entry.VisitTime
+		}
+	}
+
+	s.UniqueURLs = len(urls)
+	s.Domains = len(domains)
+	return s
+}
+
+// Site aggregates the entries of one domain.
+type Site struct {
+	Domain   string         `json:"domain"`
+	Visits   int            `json:"visits"`  // sum of the entries' visit counts
+	Entries  int            `json:"entries"` // number of entries
+	Title    string         `json:"title"`   // title of the first entry seen
+	Browsers map[string]int `json:"browsers"`
+}
+
+// TopSites aggregates entries by domain, most visited first. Ties are broken
+// by domain so the order is stable.
+func TopSites(entries []types.VisitEntry) []Site {
+	return TopSitesBy(entries, ByHost)
+}
+
+// TopSitesBy is TopSites with the sites grouped by g.
+func TopSitesBy(entries []types.VisitEntry, g Grouping) []Site {
+	index := make(map[string]int)
+	var sites []Site
+
+	for _, entry := range entries {
+		domain := SiteOf(entry.URL, g)
+		i, ok := index[domain]
+		if !ok {
+			i = len(sites)
+			index[domain] = i
+			sites = append(sites, Site{Domain: domain, Browsers: make(map[string]int)})
+		}
+
+		site := &sites[i]
+		site.Visits += entry.VisitCount
+		site.Entries++
+		if site.Title == "" {
+			site.Title = entry.Title
+		}
+		site.Browsers[entry.Browser]++
+	}
+
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i].Visits != sites[j].Visits {
+			return sites[i].Visits > sites[j].Visits
+		}
+		return sites[i].Domain < sites[j].Domain
+	})
+	return sites
+}
+
+// Breakdown formats per-browser entry counts, largest first, e.g. "Chrome 12 • Firefox 3".
+func Breakdown(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, counts[name])
+	}
+	return strings.Join(parts, " • ")
+}
+
+// Grouping selects what URLs are grouped by.
+type Grouping string
+
+const (
+	ByHost   Grouping = "host"   // e.g. mail.google.com
+	ByDomain Grouping = "domain" // the registrable domain, e.g. google.com
+)
+
+// Domain returns the host of url without a leading "www.".
+func Domain(url string) string {
+	return SiteOf(url, ByHost)
+}
+
+// SiteOf returns what url is grouped under. Web URLs are grouped by host without
+// a leading "www." or by registrable domain. Other URLs are grouped by scheme
+// and host, e.g. "chrome://settings", or by scheme alone when they have no
+// host, e.g. "about:" or "file:".
+func SiteOf(url string, g Grouping) string {
+	p := urlnorm.Parse(url)
+	switch {
+	case p.Host == "":
+		return p.Scheme + ":"
+	case p.Scheme != "http" && p.Scheme != "https" && p.Scheme != "ftp":
+		return p.Scheme + "://" + p.Host
+	case g == ByDomain:
+		return p.Domain
+	}
+	return strings.TrimPrefix(p.Host, "www.")
+}
@@ -4,9 +4,69 @@ import (
 	"time"
 )
 
+// Transition describes how the user arrived at a page, normalised across browsers.
+type Transition string
+
+const (
+	TransitionUnknown    Transition = ""
+	TransitionLink       Transition = "link"
+	TransitionTyped      Transition = "typed"
+	TransitionBookmark   Transition = "bookmark"
+	TransitionSubframe   Transition = "subframe"
+	TransitionGenerated  Transition = "generated"
+	TransitionFormSubmit Transition = "form_submit"
+	TransitionReload     Transition = "reload"
+	TransitionKeyword    Transition = "keyword"
+	TransitionRedirect   Transition = "redirect"
+	TransitionDownload   Transition = "download"
+)
+
 type VisitEntry struct {
 	URL        string    `json:"url"`
 	Title      string    `json:"title"`
 	VisitCount int       `json:"visit_count"`
 	VisitTime  time.Time `json:"visit_time"`
+
+	// Provenance: which browser and profile the visit was read from. Profile
+	// is the display name, which users can change; ProfileDir is the profile's
+	// directory and identifies it for good.
+	Browser    string `json:"browser"`
+	Profile    string `json:"profile"`
+	ProfileDir string `json:"-"`
+
+	// Visit metadata, as stored by the browser. IDs are only unique within one
+	// browser profile.
+	VisitID         int64         `json:"visit_id"`
+	URLID           int64         `json:"url_id"`
+	TypedCount      int           `json:"typed_count"`
+	Transition      Transition    `json:"transition"`
+	ReferrerVisitID int64         `json:"referrer_visit_id"` // 0 if the visit has no referrer
+	Duration        time.Duration `json:"duration"`
+}
+
+// VisitKey identifies a visit. Visit IDs are only unique within one browser
+// profile, and a profile is told apart by its directory, which survives
+// renames.
+type VisitKey struct {
+	Browser    string
+	ProfileDir string
+	VisitID    int64
+}
+
+// Key returns the key of e. Entries without a ProfileDir are keyed on their
+// display name instead.
+func (e VisitEntry) Key() VisitKey {
+	dir := e.ProfileDir
+	if dir == "" {
+		dir = e.Profile
+	}
+	return VisitKey{e.Browser, dir, e.VisitID}
+}
+
+// ReferrerKey returns the key of the visit e came from. It is only meaningful
+// when e.ReferrerVisitID is not 0.
+func (e VisitEntry) ReferrerKey() VisitKey {
+	k := e.Key()
+	k.VisitID = e.ReferrerVisitID
+	return k
 }
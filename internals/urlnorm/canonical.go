@@ -0,0 +1,149 @@
+package urlnorm
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// DefaultTrackingParams are the query parameters dropped by DefaultRules:
+// campaign tags and the click IDs of ad and social networks.
+var DefaultTrackingParams = []string{
+	"utm_*", "fbclid", "gclid", "gclsrc", "dclid", "gbraid", "wbraid", "msclkid",
+	"yclid", "twclid", "ttclid", "li_fat_id", "igshid", "mc_cid", "mc_eid",
+	"_ga", "_gl", "_hsenc", "_hsmi", "mkt_tok", "oly_anon_id", "oly_enc_id",
+	"vero_id", "rb_clickid", "s_cid", "ref_src",
+}
+
+// defaultPorts are dropped from URLs of their scheme.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+	"ftp":   "21",
+}
+
+// Rules configure Canonicalize.
+type Rules struct {
+	// TrackingParams are query parameters to drop, compared case-insensitively.
+	// A trailing "*" matches any suffix, e.g. "utm_*".
+	TrackingParams []string
+	// KeepFragment keeps the #fragment, which usually only scrolls within a page.
+	KeepFragment bool
+	// KeepMobile keeps "www.", "m." and "mobile." subdomains, which otherwise
+	// are folded into the site they belong to.
+	KeepMobile bool
+}
+
+// DefaultRules drop DefaultTrackingParams and fragments and fold mobile
+// subdomains.
+func DefaultRules() Rules {
+	return Rules{TrackingParams: DefaultTrackingParams}
+}
+
+// Canonicalize rewrites rawURL so that addresses of the same page compare
+// equal: the scheme and host are lower case, default ports, tracking
+// parameters and trailing slashes are dropped, and so are fragments and mobile
+// subdomains unless r keeps them. URLs that cannot be parsed or have no
+// scheme are returned unchanged.
+func (r Rules) Canonicalize(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" {
+		return rawURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if !r.KeepFragment {
+		u.Fragment, u.RawFragment = "", ""
+	}
+	u.RawQuery = r.stripParams(u.RawQuery)
+	u.ForceQuery = false
+	if u.Opaque != "" || u.Host == "" {
+		// about:blank, mailto: or file:///path have no host to normalise
+		return u.String()
+	}
+
+	host := strings.TrimRight(strings.ToLower(u.Hostname()), ".")
+	if !r.KeepMobile {
+		host = foldSubdomains(host)
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6
+	}
+	if port := u.Port(); port != "" && port != defaultPorts[u.Scheme] {
+		host += ":" + port
+	}
+	u.Host = host
+
+	if u.Path == "" {
+		u.Path, u.RawPath = "/", ""
+	} else if len(u.Path) > 1 && strings.HasSuffix(u.Path, "/") {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+		if u.Path == "" {
+			u.Path, u.RawPath = "/", ""
+		}
+	}
+	return u.String()
+}
+
+// stripParams drops the tracking parameters from a raw query, keeping the
+// order and encoding of the others.
+func (r Rules) stripParams(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	var kept []string
+	for _, param := range strings.Split(rawQuery, "&") {
+		if param == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if !r.isTracking(name) {
+			kept = append(kept, param)
+		}
+	}
+	return strings.Join(kept, "&")
+}
+
+func (r Rules) isTracking(name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range r.TrackingParams {
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// foldSubdomains drops a leading "www" and any "m" or "mobile" label from the
+// subdomains of host, e.g. en.m.wikipedia.org becomes en.wikipedia.org. The
+// registrable domain itself is never changed.
+func foldSubdomains(host string) string {
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	// Punycode is kept, so internationalised TLDs fall back to the "*" rule
+	domain := RegistrableDomain(host)
+	if domain == "" || !strings.HasSuffix(host, "."+domain) {
+		return host
+	}
+
+	labels := strings.Split(strings.TrimSuffix(host, "."+domain), ".")
+	var kept []string
+	for i, label := range labels {
+		if label == "m" || label == "mobile" || i == 0 && label == "www" {
+			continue
+		}
+		kept = append(kept, label)
+	}
+	return strings.Join(append(kept, domain), ".")
+}
@@ -0,0 +1,73 @@
+package urlnorm
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// publicSuffixList is https://publicsuffix.org/list/public_suffix_list.dat,
+// with both its ICANN and private sections.
+//
+//go:embed public_suffix_list.dat
+var publicSuffixList string
+
+var (
+	rulesOnce sync.Once
+	// rules holds every rule as written in the list: "com", "*.ck", "!www.ck".
+	rules map[string]bool
+)
+
+func loadRules() {
+	rules = make(map[string]bool, 10000)
+	for _, line := range strings.Split(publicSuffixList, "\n") {
+		// Rules end at the first whitespace
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "//") {
+			continue
+		}
+		rules[strings.ToLower(fields[0])] = true
+	}
+}
+
+// PublicSuffix returns the public suffix of a normalised host, such as "com"
+// for mail.google.com or "co.uk" for bbc.co.uk. Hosts matching no rule get
+// their last label, as the list's default "*" rule says.
+func PublicSuffix(host string) string {
+	rulesOnce.Do(loadRules)
+
+	labels := strings.Split(host, ".")
+	// The first match from the left is the longest. An exception rule is
+	// longer than the wildcard it overrides, so it is found first.
+	for i := range labels {
+		candidate := strings.Join(labels[i:], ".")
+		if rules["!"+candidate] {
+			return strings.Join(labels[i+1:], ".")
+		}
+		if rules[candidate] {
+			return candidate
+		}
+		if i+1 < len(labels) && rules["*."+strings.Join(labels[i+1:], ".")] {
+			return candidate
+		}
+	}
+	return labels[len(labels)-1]
+}
+
+// RegistrableDomain returns the public suffix of a normalised host plus one
+// label, e.g. "google.com" for mail.google.com. It returns "" if host is itself
+// a public suffix.
+func RegistrableDomain(host string) string {
+	suffix := PublicSuffix(host)
+	if len(host) <= len(suffix) {
+		return ""
+	}
+	rest := strings.TrimSuffix(host[:len(host)-len(suffix)], ".")
+	if i := strings.LastIndexByte(rest, '.'); i >= 0 {
+		rest = rest[i+1:]
+	}
+	if rest == "" {
+		return ""
+	}
+	return rest + "." + suffix
+}
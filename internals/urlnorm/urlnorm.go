@@ -0,0 +1,83 @@
+// Package urlnorm splits history URLs into the parts they are grouped by: the
+// scheme, the host and the registrable domain from the Public Suffix List.
+package urlnorm
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Parts are the normalised parts of a URL.
+type Parts struct {
+	// Scheme is lower case, e.g. "https", "file", "chrome" or "about".
+	Scheme string
+	// Host is lower case, without userinfo, port or trailing dot. Punycode
+	// labels of internationalised names are decoded. Empty for URLs without
+	// a host, such as about:blank or file:///etc/hosts.
+	Host string
+	// Domain is the registrable domain (eTLD+1) of Host, e.g. "google.com" for
+	// mail.google.com. It is Host itself for IP addresses, single-label hosts
+	// like localhost, and hosts that are a public suffix.
+	Domain string
+}
+
+// Parse normalises rawURL. URLs that net/url rejects, for example because of
+// a bad escape in the path, are parsed from their scheme and authority only.
+func Parse(rawURL string) Parts {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		u, err = url.Parse(authority(rawURL))
+		if err != nil {
+			return Parts{Scheme: scheme(rawURL)}
+		}
+	}
+
+	p := Parts{Scheme: strings.ToLower(u.Scheme), Host: normalizeHost(u.Hostname())}
+	p.Domain = p.Host
+	if p.Host != "" && net.ParseIP(p.Host) == nil {
+		if domain := RegistrableDomain(p.Host); domain != "" {
+			p.Domain = domain
+		}
+	}
+	return p
+}
+
+// authority returns rawURL up to the end of its host and port.
+func authority(rawURL string) string {
+	i := strings.Index(rawURL, "://")
+	if i < 0 {
+		return scheme(rawURL) + ":"
+	}
+	if end := strings.IndexAny(rawURL[i+3:], "/?#"); end >= 0 {
+		return rawURL[:i+3+end]
+	}
+	return rawURL
+}
+
+// scheme returns the lower case scheme of rawURL, or "" if it has none.
+func scheme(rawURL string) string {
+	s, _, ok := strings.Cut(rawURL, ":")
+	if !ok || s == "" || strings.ContainsAny(s, "/?#") {
+		return ""
+	}
+	return strings.ToLower(s)
+}
+
+// normalizeHost lower-cases host, drops a trailing dot and decodes punycode
+// labels. Labels that are not valid punycode are kept as they are.
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if !strings.Contains(host, "xn--") {
+		return host
+	}
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		if encoded, ok := strings.CutPrefix(label, "xn--"); ok {
+			if decoded, err := decodePunycode(encoded); err == nil {
+				labels[i] = strings.ToLower(decoded)
+			}
+		}
+	}
+	return strings.Join(labels, ".")
+}
@@ -0,0 +1,139 @@
+package parse_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/render"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runCmd executes cmd and feeds the messages it produces back into m, as the
+// program would. Spinner ticks and quitting are left out.
+func runCmd(t *testing.T, m tea.Model, cmd tea.Cmd) tea.Model {
+	t.Helper()
+	if cmd == nil {
+		return m
+	}
+	switch msg := cmd().(type) {
+	case nil, spinner.TickMsg, tea.QuitMsg:
+	case tea.BatchMsg:
+		for _, c := range msg {
+			m = runCmd(t, m, c)
+		}
+	default:
+		m, cmd = m.Update(msg)
+		m = runCmd(t, m, cmd)
+	}
+	return m
+}
+
+func TestApp_StateMachine(t *testing.T) {
+	isolateSources(t)
+
+	var loads []render.BrowserChoice
+	load := func(choice render.BrowserChoice) ([]types.VisitEntry, error) {
+		loads = append(loads, choice)
+		if len(loads) == 1 {
+			return nil, errors.New("database is locked")
+		}
+		return []types.VisitEntry{{URL: "https://example.com/", Title: "Example", VisitCount: 1,
+			VisitTime: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)}}, nil
+	}
+	var m tea.Model = render.NewAppModel(load, render.BrowserChoice{Browser: "Firefox"}, render.ViewerOptions{Location: time.UTC})
+	if view := m.View(); !strings.Contains(view, "Fetching Firefox history") {
+		t.Fatalf("expected the loading screen, got:\n%s", view)
+	}
+
+	// The first load fails and shows the error with a retry
+	m = runCmd(t, m, m.Init())
+	if view := m.View(); !strings.Contains(view, "Error: database is locked") || !strings.Contains(view, "enter to retry") {
+		t.Fatalf("expected the error screen, got:\n%s", view)
+	}
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = runCmd(t, m, cmd)
+	if view := m.View(); !strings.Contains(view, "Browser History Analyzer") || !strings.Contains(view, "example.com") {
+		t.Fatalf("expected the viewer after retrying, got:\n%s", view)
+	}
+
+	// b goes back to the menu, and picking a browser loads it in the same program
+	m, cmd = m.Update(key("b"))
+	m = runCmd(t, m, cmd)
+	if view := m.View(); !strings.Contains(view, "choose a browser") {
+		t.Fatalf("expected the browser menu, got:\n%s", view)
+	}
+
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = runCmd(t, m, cmd)
+	if len(loads) != 3 || loads[2].Browser == "" {
+		t.Fatalf("expected a third load of the chosen browser, got %v", loads)
+	}
+	if view := m.View(); !strings.Contains(view, "Browser History Analyzer") {
+		t.Fatalf("expected the viewer, got:\n%s", view)
+	}
+}
+
+func TestApp_AbandonedLoad(t *testing.T) {
+	isolateSources(t)
+
+	load := func(render.BrowserChoice) ([]types.VisitEntry, error) {
+		return []types.VisitEntry{{URL: "https://example.com/", VisitTime: time.Now()}}, nil
+	}
+	var m tea.Model = render.NewAppModel(load, render.BrowserChoice{Browser: "Chrome"}, render.ViewerOptions{})
+	pending := m.Init()
+
+	// Leaving the loading screen drops the result of the load
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = runCmd(t, m, pending)
+	if view := m.View(); strings.Contains(view, "Browser History Analyzer") || !strings.Contains(view, "choose a browser") {
+		t.Fatalf("expected to stay in the browser menu, got:\n%s", view)
+	}
+}
+
+// Choosing while the menu filter matches no browser leaves the menu as it is.
+func TestApp_MenuEnterWithNoMatch(t *testing.T) {
+	isolateSources(t)
+	load := func(render.BrowserChoice) ([]types.VisitEntry, error) { return nil, nil }
+
+	// filterMatches runs the filtering command from cmd and feeds its result to
+	// m. The cursor blink is left out, as it would never stop.
+	var filterMatches func(m tea.Model, cmd tea.Cmd) tea.Model
+	filterMatches = func(m tea.Model, cmd tea.Cmd) tea.Model {
+		if cmd == nil {
+			return m
+		}
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				m = filterMatches(m, c)
+			}
+		case list.FilterMatchesMsg:
+			m, _ = m.Update(msg)
+		}
+		return m
+	}
+
+	var m tea.Model = render.NewAppModel(load, render.BrowserChoice{}, render.ViewerOptions{})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m, filtering := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("zzz")})
+	// The filter is accepted before its matches come in, leaving nothing to choose.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = filterMatches(m, filtering)
+	if view := m.View(); !strings.Contains(view, "No items") {
+		t.Fatalf("expected an empty filtered menu, got:\n%s", view)
+	}
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Errorf("expected nothing to be chosen, got %T", cmd())
+	}
+	if view := m.View(); !strings.Contains(view, "No items") {
+		t.Errorf("expected the menu to stay, got:\n%s", view)
+	}
+}
@@ -0,0 +1,204 @@
+package parse_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/archive"
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+func openArchive(t *testing.T) *archive.Archive {
+	t.Helper()
+	a, err := archive.Open(filepath.Join(t.TempDir(), "histograph", "archive.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { a.Close() })
+	return a
+}
+
+func TestArchive_DefaultPath(t *testing.T) {
+	t.Setenv("HISTOGRAPH_ARCHIVE_PATH", "")
+	t.Setenv("XDG_DATA_HOME", "/tmp/xdg")
+
+	path, err := archive.DefaultPath()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != filepath.Join("/tmp/xdg", "histograph", "archive.db") {
+		t.Errorf("unexpected archive path %s", path)
+	}
+
+	t.Setenv("HISTOGRAPH_ARCHIVE_PATH", "/tmp/fake_archive.db")
+	if path, _ := archive.DefaultPath(); path != "/tmp/fake_archive.db" {
+		t.Errorf("expected env override, got %s", path)
+	}
+}
+
+func TestArchive_IngestDeduplicates(t *testing.T) {
+	a := openArchive(t)
+	base := time.Date(2025, 1, 1, 12, 0, 0, 123456000, time.UTC)
+
+	first := []types.VisitEntry{
+		{URL: "https://a.example/", Title: "A", VisitCount: 1, VisitTime: base,
+			Browser: "Chrome", Profile: "Work", ProfileDir: "Profile 1", VisitID: 1, URLID: 7, TypedCount: 1,
+			Transition: types.TransitionTyped, Duration: 1500 * time.Millisecond},
+		{URL: "https://b.example/", Title: "B", VisitCount: 1, VisitTime: base.Add(time.Minute),
+			Browser: "Chrome", Profile: "Work", ProfileDir: "Profile 1", VisitID: 2, ReferrerVisitID: 1},
+		// Same visit ID in a different profile is a different visit.
+		{URL: "https://c.example/", Title: "C", VisitCount: 1, VisitTime: base.Add(2 * time.Minute),
+			Browser: "Chrome", Profile: "Personal", ProfileDir: "Profile 2", VisitID: 1},
+	}
+	added, err := a.Ingest(first)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if added != 3 {
+		t.Errorf("expected 3 new visits, got %d", added)
+	}
+
+	// The browser has since expired visit 1 and bumped the title and count of visit 2.
+	second := []types.VisitEntry{
+		{URL: "https://b.example/", Title: "B renamed", VisitCount: 4, VisitTime: base.Add(time.Minute),
+			Browser: "Chrome", Profile: "Work", ProfileDir: "Profile 1", VisitID: 2, ReferrerVisitID: 1},
+		{URL: "https://d.example/", Title: "D", VisitCount: 1, VisitTime: base.Add(3 * time.Minute),
+			Browser: "Chrome", Profile: "Work", ProfileDir: "Profile 1", VisitID: 3},
+	}
+	added, err = a.Ingest(second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("expected 1 new visit, got %d", added)
+	}
+
+	entries, err := a.Entries(archive.Query{Browser: "Chrome", Profiles: []string{"Profile 1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 Work visits, got %+v", entries)
+	}
+	if entries[0].URL != "https://d.example/" || entries[1].Title != "B renamed" || entries[1].VisitCount != 4 {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+	if got := entries[2]; got != first[0] {
+		t.Errorf("expected expired visit to round-trip unchanged:\n got %+v\nwant %+v", got, first[0])
+	}
+}
+
+// Renaming a profile keeps its archived history under the same directory.
+func TestArchive_IngestFollowsProfileRename(t *testing.T) {
+	a := openArchive(t)
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err := a.Ingest([]types.VisitEntry{
+		{URL: "https://a.example/", VisitTime: base, Browser: "Chrome", Profile: "Work", ProfileDir: "Profile 1", VisitID: 1},
+		{URL: "https://b.example/", VisitTime: base.Add(time.Minute), Browser: "Chrome", Profile: "Work", ProfileDir: "Profile 1", VisitID: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	added, err := a.Ingest([]types.VisitEntry{
+		{URL: "https://b.example/", VisitTime: base.Add(time.Minute), Browser: "Chrome", Profile: "Job", ProfileDir: "Profile 1", VisitID: 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if added != 0 {
+		t.Errorf("expected the renamed profile's visit to be known, got %d new", added)
+	}
+
+	entries, err := a.Entries(archive.Query{Profiles: []string{"Profile 1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 visits, got %+v", entries)
+	}
+	for _, e := range entries {
+		if e.Profile != "Job" {
+			t.Errorf("expected every visit under the new name, got %+v", e)
+		}
+	}
+}
+
+func TestArchive_EntriesQuery(t *testing.T) {
+	a := openArchive(t)
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 12, 0, 0, 0, time.UTC) }
+
+	_, err := a.Ingest([]types.VisitEntry{
+		{URL: "https://github.com/", VisitTime: day(1), Browser: "Chrome", Profile: "Default", VisitID: 1},
+		{URL: "https://gist.github.com/", VisitTime: day(2), Browser: "Firefox", Profile: "default", VisitID: 1},
+		{URL: "https://example.com/", VisitTime: day(3), Browser: "Firefox", Profile: "default", VisitID: 2},
+		{URL: "https://github.com/x", VisitTime: day(4), Browser: "Chrome", Profile: "Default", VisitID: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query archive.Query
+		want  []string
+	}{
+		{"everything", archive.Query{}, []string{"https://github.com/x", "https://example.com/", "https://gist.github.com/", "https://github.com/"}},
+		{"browser", archive.Query{Browser: "Firefox"}, []string{"https://example.com/", "https://gist.github.com/"}},
+		{"domain", archive.Query{Options: parse.Options{Domain: "github.com"}}, []string{"https://github.com/x", "https://gist.github.com/", "https://github.com/"}},
+		{"window and limit", archive.Query{Options: parse.Options{Since: day(2), Until: day(4), Limit: 1}}, []string{"https://example.com/"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := a.Entries(tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("expected %v, got %+v", tt.want, entries)
+			}
+			for i, e := range entries {
+				if e.URL != tt.want[i] {
+					t.Errorf("entry %d: expected %s, got %s", i, tt.want[i], e.URL)
+				}
+			}
+		})
+	}
+}
+
+func TestArchive_RefreshAppliesLimit(t *testing.T) {
+	a := openArchive(t)
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	visits := func(from, n int) []types.VisitEntry {
+		var entries []types.VisitEntry
+		for i := from; i < from+n; i++ {
+			entries = append(entries, types.VisitEntry{URL: "https://example.com/", VisitTime: base.Add(time.Duration(i) * time.Minute),
+				Browser: "Chrome", Profile: "Default", VisitID: int64(i)})
+		}
+		return entries
+	}
+
+	q := archive.Query{Options: parse.Options{Limit: 10}, Browser: "Chrome"}
+	if _, _, err := a.Refresh(visits(0, 10), q); err != nil {
+		t.Fatal(err)
+	}
+	// Once the archive holds more than the limit, only the newest visits are read back.
+	entries, added, err := a.Refresh(visits(10, 10), q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if added != 10 {
+		t.Errorf("expected 10 new visits, got %d", added)
+	}
+	if len(entries) != 10 || entries[0].VisitID != 19 || entries[9].VisitID != 10 {
+		t.Errorf("expected the newest 10 visits, got %+v", entries)
+	}
+
+	q.Limit = 0
+	if entries, _, _ := a.Refresh(nil, q); len(entries) != 20 {
+		t.Errorf("expected every archived visit without a limit, got %d", len(entries))
+	}
+}
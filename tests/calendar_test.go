@@ -0,0 +1,54 @@
+package parse_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/render"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCalendarView(t *testing.T) {
+	// 2025-01-06 is a Monday
+	monday := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	entries := []types.VisitEntry{
+		{URL: "https://news.example/", Title: "Weekday news", VisitCount: 1, VisitTime: monday.Add(48 * time.Hour)},
+		{URL: "https://github.com/a", Title: "Monday repo", VisitCount: 1, VisitTime: monday},
+		{URL: "https://github.com/b", Title: "Last week", VisitCount: 1, VisitTime: monday.AddDate(0, 0, -7)},
+	}
+
+	var m tea.Model = render.NewChromeHistoryModel(entries, render.ViewerOptions{Location: time.UTC}, 160, 40)
+
+	// The cursor starts on the most recent day with visits
+	press(t, &m, key("7"))
+	view := m.View()
+	if !strings.Contains(view, "Wed Jan 8, 2025: 1 visits • top: news.example") {
+		t.Fatalf("expected the latest day to be selected, got:\n%s", view)
+	}
+
+	// Two days up, then a week left
+	press(t, &m, tea.KeyMsg{Type: tea.KeyUp})
+	press(t, &m, tea.KeyMsg{Type: tea.KeyUp})
+	if view = m.View(); !strings.Contains(view, "Mon Jan 6, 2025: 1 visits • top: github.com") {
+		t.Fatalf("expected Monday to be selected, got:\n%s", view)
+	}
+	press(t, &m, tea.KeyMsg{Type: tea.KeyLeft})
+	if view = m.View(); !strings.Contains(view, "Mon Dec 30, 2024: 1 visits") {
+		t.Fatalf("expected the previous Monday to be selected, got:\n%s", view)
+	}
+
+	// Enter lists that day's visits in Details
+	press(t, &m, tea.KeyMsg{Type: tea.KeyEnter})
+	view = m.View()
+	if !strings.Contains(view, "Last week") || strings.Contains(view, "Monday repo") {
+		t.Fatalf("expected Details for Dec 30 only, got:\n%s", view)
+	}
+
+	// Esc shows every day again
+	press(t, &m, tea.KeyMsg{Type: tea.KeyEsc})
+	if view = m.View(); !strings.Contains(view, "Monday repo") {
+		t.Fatalf("expected every day after esc, got:\n%s", view)
+	}
+}
@@ -0,0 +1,98 @@
+package parse_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/render"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	"github.com/akshatsrivastava11/Histograph/internals/urlnorm"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCanonicalize(t *testing.T) {
+	cases := []struct{ url, want string }{
+		{"HTTPS://Example.COM:443/Docs/?utm_source=x&id=7&UTM_Medium=y#intro", "https://example.com/Docs?id=7"},
+		{"http://example.com:80", "http://example.com/"},
+		{"http://example.com:8080/a//", "http://example.com:8080/a"},
+		{"https://www.example.com/?fbclid=abc", "https://example.com/"},
+		{"https://m.youtube.com/watch?v=dQw4w9WgXcQ&si=1", "https://youtube.com/watch?v=dQw4w9WgXcQ&si=1"},
+		{"https://en.m.wikipedia.org/wiki/Go", "https://en.wikipedia.org/wiki/Go"},
+		{"https://m.co/", "https://m.co/"}, // a registrable domain is never folded
+		{"https://mobile.bbc.co.uk/news/", "https://bbc.co.uk/news"},
+		{"http://[::1]:80/x", "http://[::1]/x"},
+		{"https://example.com/a%2Fb/", "https://example.com/a%2Fb"},
+		{"https://example.com/search?q=a+b&gclid=1&&", "https://example.com/search?q=a+b"},
+		{"about:blank#top", "about:blank"},
+		{"file:///home/me/notes.html#l1", "file:///home/me/notes.html"},
+		{"http://bad%zz/", "http://bad%zz/"},
+	}
+	rules := urlnorm.DefaultRules()
+	for _, c := range cases {
+		if got := rules.Canonicalize(c.url); got != c.want {
+			t.Errorf("Canonicalize(%q) = %q, expected %q", c.url, got, c.want)
+		}
+	}
+
+	custom := urlnorm.Rules{TrackingParams: []string{"ref", "session*"}, KeepFragment: true, KeepMobile: true}
+	got := custom.Canonicalize("https://m.example.com/p/?ref=hn&Session_ID=3&utm_source=x#comments")
+	if want := "https://m.example.com/p?utm_source=x#comments"; got != want {
+		t.Errorf("custom rules: got %q, expected %q", got, want)
+	}
+}
+
+func FuzzCanonicalize(f *testing.F) {
+	f.Add("HTTPS://Example.COM:443/Docs/?utm_source=x&id=7#intro")
+	f.Add("https://en.m.wikipedia.org/wiki/Go")
+	f.Add("file:///tmp/x")
+	f.Add("about:blank")
+	rules := urlnorm.DefaultRules()
+	f.Fuzz(func(t *testing.T, rawURL string) {
+		once := rules.Canonicalize(rawURL)
+		if twice := rules.Canonicalize(once); twice != once {
+			t.Fatalf("Canonicalize is not idempotent: %q -> %q -> %q", rawURL, once, twice)
+		}
+	})
+}
+
+func TestViewer_AggregatesCanonicalURLs(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	entries := []types.VisitEntry{
+		{URL: "https://m.example.com/post/?utm_source=feed", Title: "Post", VisitCount: 1, VisitTime: base.Add(2 * time.Minute), Browser: "Chrome", VisitID: 3},
+		{URL: "https://www.example.com/post#comments", Title: "Post", VisitCount: 1, VisitTime: base.Add(time.Minute), Browser: "Chrome", VisitID: 2},
+		{URL: "https://example.com/post", Title: "Post", VisitCount: 1, VisitTime: base, Browser: "Chrome", VisitID: 1},
+	}
+	var copied string
+	opts := render.ViewerOptions{Location: time.UTC, Copy: func(text string) error { copied = text; return nil }}
+	var m tea.Model = render.NewChromeHistoryModel(entries, opts, 120, 60)
+
+	press(t, &m, key("3"))
+	if view := m.View(); !strings.Contains(view, "example.com") || strings.Contains(view, "m.example.com") {
+		t.Fatalf("expected one example.com site, got:\n%s", view)
+	}
+
+	// Details shows and copies the URL as visited, but every visit of the page
+	// is listed together, each under the URL it was made at
+	press(t, &m, key("4"))
+	if view := m.View(); !strings.Contains(view, "https://m.example.com/post/?utm_source=feed") {
+		t.Fatalf("expected the original URL in Details, got:\n%s", view)
+	}
+	press(t, &m, key("c"))
+	if copied != "https://m.example.com/post/?utm_source=feed" {
+		t.Errorf("expected the original URL to be copied, got %q", copied)
+	}
+	press(t, &m, tea.KeyMsg{Type: tea.KeyEnter})
+	view := m.View()
+	for _, want := range []string{"3 visits loaded", "https://m.example.com/post/?utm_source=feed", "https://www.example.com/post"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("visits pane does not contain %q:\n%s", want, view)
+		}
+	}
+	press(t, &m, tea.KeyMsg{Type: tea.KeyEsc})
+
+	press(t, &m, key("g"))
+	if view := m.View(); !strings.Contains(view, "https://m.example.com/post/?utm_source=feed") {
+		t.Errorf("expected the original URL in the trail, got:\n%s", view)
+	}
+}
@@ -0,0 +1,89 @@
+package parse_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildHistograph compiles the histograph command into a temporary directory.
+func buildHistograph(t *testing.T) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "histograph")
+	out, err := exec.Command("go", "build", "-o", bin, "../cmd/Histograph").CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to build histograph: %v\n%s", err, out)
+	}
+	return bin
+}
+
+func TestCLI_UnknownFlagIsUsageError(t *testing.T) {
+	bin := buildHistograph(t)
+
+	cmd := exec.Command(bin, "list", "--no-such-flag")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Fatalf("expected exit status 2, got %v", err)
+	}
+	if n := strings.Count(stderr.String(), "-no-such-flag"); n != 1 {
+		t.Errorf("expected the error to be printed once, got:\n%s", stderr.String())
+	}
+}
+
+// --limit caps what the commands read back from the archive too, so it still
+// applies once earlier runs have archived more visits than the limit.
+func TestCLI_LimitAppliesToArchivedVisits(t *testing.T) {
+	bin := buildHistograph(t) // before isolateSources moves HOME and the build cache
+	isolateSources(t)
+
+	dir := t.TempDir()
+	var visits []chromeVisit
+	for i := 0; i < 30; i++ {
+		visits = append(visits, chromeVisit{url: fmt.Sprintf("https://site%d.example/%d", i%3, i),
+			title: fmt.Sprintf("Page %d", i), visitTime: 13379755200000000 + int64(i)*1000000})
+	}
+	historyPath := filepath.Join(dir, "History")
+	createChromeHistory(t, historyPath, visits)
+	t.Setenv("CHROME_HISTORY_PATH", historyPath)
+	t.Setenv("HISTOGRAPH_ARCHIVE_PATH", filepath.Join(dir, "archive.db"))
+
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command(bin, args...).Output()
+		if err != nil {
+			t.Fatalf("histograph %s: %v", strings.Join(args, " "), err)
+		}
+		return string(out)
+	}
+
+	// A run without --limit archives every visit.
+	run("list", "--browser", "chrome", "--limit", "0")
+
+	for i := 1; i <= 2; i++ {
+		list := strings.Split(strings.TrimSpace(run("list", "--browser", "chrome", "--limit", "5")), "\n")
+		if len(list) != 5 || !strings.Contains(list[0], "Page 29") {
+			t.Errorf("run %d: expected the newest 5 visits, got:\n%s", i, strings.Join(list, "\n"))
+		}
+
+		var sites []struct{ Entries int }
+		if err := json.Unmarshal([]byte(run("top", "--browser", "chrome", "--limit", "5", "--format", "json")), &sites); err != nil {
+			t.Fatal(err)
+		}
+		total := 0
+		for _, site := range sites {
+			total += site.Entries
+		}
+		if total != 5 {
+			t.Errorf("run %d: expected top to count 5 visits, got %d", i, total)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package parse_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/render"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDetails_Actions(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	entries := []types.VisitEntry{
+		{URL: "https://go.dev/doc/", Title: "Go docs", VisitCount: 2, VisitTime: base.Add(2 * time.Hour), Browser: "Chrome", VisitID: 3},
+		{URL: "https://example.com/", Title: "Example", VisitCount: 1, VisitTime: base.Add(time.Hour), Browser: "Chrome", VisitID: 2},
+		{URL: "https://go.dev/doc/", Title: "Go docs", VisitCount: 2, VisitTime: base, Browser: "Firefox", Profile: "work", VisitID: 1,
+			Transition: types.TransitionTyped, Duration: 5 * time.Minute},
+	}
+
+	var opened, copied []string
+	opts := render.ViewerOptions{
+		Location: time.UTC,
+		Open:     func(url string) error { opened = append(opened, url); return nil },
+		Copy: func(text string) error {
+			copied = append(copied, text)
+			return fmt.Errorf("no clipboard")
+		},
+	}
+	var m tea.Model = render.NewChromeHistoryModel(entries, opts, 120, 60)
+
+	press(t, &m, key("4"))
+	press(t, &m, tea.KeyMsg{Type: tea.KeyDown})
+	if view := m.View(); !strings.Contains(view, "▶ Example") {
+		t.Fatalf("expected Example to be selected, got:\n%s", view)
+	}
+
+	press(t, &m, key("o"))
+	if len(opened) != 1 || opened[0] != "https://example.com/" {
+		t.Errorf("expected example.com to be opened, got %v", opened)
+	}
+	if view := m.View(); !strings.Contains(view, "Opened https://example.com/") {
+		t.Errorf("expected an opened status, got:\n%s", view)
+	}
+
+	press(t, &m, tea.KeyMsg{Type: tea.KeyUp})
+	press(t, &m, key("c"))
+	if len(copied) != 1 || copied[0] != "https://go.dev/doc/" {
+		t.Errorf("expected the go.dev URL to be copied, got %v", copied)
+	}
+	if view := m.View(); !strings.Contains(view, "no clipboard") {
+		t.Errorf("expected the copy error to be shown, got:\n%s", view)
+	}
+
+	// enter lists every visit of the URL, across browsers
+	press(t, &m, tea.KeyMsg{Type: tea.KeyEnter})
+	view := m.View()
+	for _, want := range []string{"Every Visit", "2 visits loaded", "▶ Wed Jan 1 2025, 11:00", "Wed Jan 1 2025, 09:00 Firefox (work) • typed • 5m"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("visits pane does not contain %q:\n%s", want, view)
+		}
+	}
+	press(t, &m, tea.KeyMsg{Type: tea.KeyEsc})
+	if view := m.View(); strings.Contains(view, "Every Visit") {
+		t.Fatalf("expected esc to go back to the list, got:\n%s", view)
+	}
+}
+
+func TestDetails_SelectionStaysInView(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var entries []types.VisitEntry
+	for i := 0; i < 50; i++ {
+		entries = append(entries, types.VisitEntry{
+			URL:   fmt.Sprintf("https://example.com/%d", i),
+			Title: fmt.Sprintf("Page %d", i), VisitCount: 1,
+			VisitTime: base.Add(-time.Duration(i) * time.Minute),
+		})
+	}
+	// A title holding the selection marker must not be mistaken for the selection.
+	entries[0].Title = "▶ Play all"
+
+	var m tea.Model = render.NewChromeHistoryModel(entries, render.ViewerOptions{Location: time.UTC}, 120, 30)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("4")})
+	for i := 0; i < 30; i++ {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	view := m.View()
+	if !strings.Contains(view, "▶ Page 30") || strings.Contains(view, "Play all") {
+		t.Fatalf("expected the viewport to follow the selection, got:\n%s", view)
+	}
+	// Only the entries on screen are rendered, under a count of them all.
+	if !strings.Contains(view, "of 50") {
+		t.Errorf("expected the window of entries to be counted, got:\n%s", view)
+	}
+}
+
+func TestOpenURL_RejectsOtherSchemes(t *testing.T) {
+	for _, url := range []string{"javascript:alert(1)", "chrome://settings", "-n"} {
+		if err := render.OpenURL(url); err == nil {
+			t.Errorf("expected %q to be rejected", url)
+		}
+	}
+}
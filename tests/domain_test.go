@@ -0,0 +1,105 @@
+package parse_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/render"
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func domainEntries() []types.VisitEntry {
+	base := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	return []types.VisitEntry{
+		{URL: "https://news.example/", Title: "News", VisitCount: 9, VisitTime: base, Browser: "Chrome", VisitID: 1},
+		{URL: "https://news.example/a", Title: "A", VisitCount: 9, VisitTime: base.Add(time.Minute), Browser: "Chrome", VisitID: 2},
+		{URL: "https://github.com/golang/go?tab=issues", Title: "Old title", VisitCount: 1, VisitTime: base.Add(2 * time.Minute),
+			Browser: "Chrome", VisitID: 3, ReferrerVisitID: 2},
+		{URL: "https://github.com/golang/go#readme", Title: "golang/go", VisitCount: 1, VisitTime: base.AddDate(0, 0, 2).Add(5 * time.Hour),
+			Browser: "Chrome", VisitID: 4},
+		{URL: "https://github.com/", Title: "GitHub", VisitCount: 1, VisitTime: base.AddDate(0, 0, 3),
+			Browser: "Chrome", VisitID: 5, ReferrerVisitID: 4},
+	}
+}
+
+func TestDomainSummary(t *testing.T) {
+	d := stats.DomainSummary(domainEntries(), "github.com", stats.ByHost, time.UTC)
+
+	if d.Visits != 3 || d.Days != 4 || len(d.Daily) != 3 {
+		t.Errorf("expected 3 visits on 3 of 4 days, got %d visits on %d of %d days", d.Visits, len(d.Daily), d.Days)
+	}
+	if got := d.PerDay(); got != 0.75 {
+		t.Errorf("expected 0.75 visits per day, got %v", got)
+	}
+	if d.Hours[9] != 2 || d.Hours[14] != 1 {
+		t.Errorf("unexpected hours %v", d.Hours)
+	}
+	if len(d.Paths) != 2 || d.Paths[0].Path != "/golang/go" || d.Paths[0].Visits != 2 || d.Paths[0].Title != "golang/go" {
+		t.Errorf("unexpected paths %+v", d.Paths)
+	}
+	if d.Paths[1].Path != "/" {
+		t.Errorf("expected the root path, got %+v", d.Paths[1])
+	}
+
+	if empty := stats.DomainSummary(domainEntries(), "nowhere.example", stats.ByHost, time.UTC); empty.Visits != 0 || empty.PerDay() != 0 {
+		t.Errorf("expected no visits, got %+v", empty)
+	}
+}
+
+func TestDomainPage(t *testing.T) {
+	var m tea.Model = render.NewChromeHistoryModel(domainEntries(), render.ViewerOptions{Location: time.UTC}, 120, 80)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 80})
+	// Top Sites is ordered by visit count, so news.example comes first
+	m, _ = m.Update(key("3"))
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if view := m.View(); !strings.Contains(view, "▶ github.com") {
+		t.Fatalf("expected github.com to be selected, got:\n%s", view)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	view := m.View()
+	for _, want := range []string{
+		"🌐 github.com", "3 visits on 3 of 4 days", "0.8 per day",
+		"First seen Mar 1, 2025 09:02", "last seen Mar 4, 2025 09:00",
+		"2025-03-03", "/golang/go", "news.example (1)",
+	} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("domain page does not contain %q:\n%s", want, view)
+		}
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if view := m.View(); !strings.Contains(view, "Top Sites") || strings.Contains(view, "First seen") {
+		t.Fatalf("expected esc to go back to Top Sites, got:\n%s", view)
+	}
+}
+
+func TestTopSites_GroupByRegistrableDomain(t *testing.T) {
+	base := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	entries := []types.VisitEntry{
+		{URL: "https://mail.google.com/mail/", Title: "Inbox", VisitCount: 1, VisitTime: base},
+		{URL: "https://docs.google.com/document/d/1", Title: "Doc", VisitCount: 1, VisitTime: base.Add(time.Minute)},
+		{URL: "https://www.bbc.co.uk/news", Title: "News", VisitCount: 1, VisitTime: base.Add(2 * time.Minute)},
+	}
+	var m tea.Model = render.NewChromeHistoryModel(entries, render.ViewerOptions{Location: time.UTC}, 120, 60)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	if view := m.View(); !strings.Contains(view, "mail.google.com") || !strings.Contains(view, "by host") {
+		t.Fatalf("expected sites grouped by host, got:\n%s", view)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	view := m.View()
+	if !strings.Contains(view, "▶ google.com") || !strings.Contains(view, "bbc.co.uk") ||
+		strings.Contains(view, "mail.google.com") || !strings.Contains(view, "by domain") {
+		t.Fatalf("expected sites grouped by registrable domain, got:\n%s", view)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if view := m.View(); !strings.Contains(view, "2 visits on 1 of 1 days") || !strings.Contains(view, "/mail") {
+		t.Fatalf("expected the google.com page to cover every subdomain, got:\n%s", view)
+	}
+}
@@ -0,0 +1,125 @@
+package parse_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/export"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+func exportEntries() []types.VisitEntry {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 123456000, time.UTC)
+	return []types.VisitEntry{
+		{URL: "https://a.example/?q=1,2", Title: `Say "hi", then` + "\nleave", VisitCount: 2, VisitTime: base,
+			Browser: "Chrome", Profile: "Work", VisitID: 2, URLID: 7, TypedCount: 1,
+			Transition: types.TransitionTyped, ReferrerVisitID: 1, Duration: 1500 * time.Millisecond},
+		{URL: "https://b.example/", Title: "B", VisitCount: 1, VisitTime: base.Add(-time.Minute),
+			Browser: "Firefox", Profile: "default-release", VisitID: 1},
+	}
+}
+
+func TestExport_JSON(t *testing.T) {
+	entries := exportEntries()
+	var buf bytes.Buffer
+	if err := export.Write(&buf, entries, export.JSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []types.VisitEntry
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, entries)
+	}
+	if !strings.HasPrefix(buf.String(), "[\n  {\n    \"url\"") {
+		t.Errorf("expected indented JSON, got %q", buf.String()[:20])
+	}
+}
+
+func TestExport_NDJSON(t *testing.T) {
+	entries := exportEntries()
+	var buf bytes.Buffer
+	if err := export.Write(&buf, entries, export.NDJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(entries) {
+		t.Fatalf("expected %d lines, got %d", len(entries), len(lines))
+	}
+	for i, line := range lines {
+		var got types.VisitEntry
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if !reflect.DeepEqual(got, entries[i]) {
+			t.Errorf("line %d: got %+v, want %+v", i, got, entries[i])
+		}
+	}
+}
+
+func TestExport_CSV(t *testing.T) {
+	entries := exportEntries()
+	var buf bytes.Buffer
+	if err := export.Write(&buf, entries, export.CSV); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), strings.Join(export.Columns, ",")+"\r\n") {
+		t.Errorf("expected a CRLF-terminated header, got %q", buf.String())
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != len(entries)+1 {
+		t.Fatalf("expected %d records, got %d", len(entries)+1, len(records))
+	}
+	want := []string{"https://a.example/?q=1,2", "Say \"hi\", then\nleave", "2", "2025-01-01T12:00:00.123456Z",
+		"Chrome", "Work", "2", "7", "1", "typed", "1", "1500000000"}
+	if !reflect.DeepEqual(records[1], want) {
+		t.Errorf("unexpected first row:\n got %q\nwant %q", records[1], want)
+	}
+}
+
+// The CSV columns follow VisitEntry's JSON fields, so both exports line up.
+func TestExport_ColumnsMatchJSON(t *testing.T) {
+	typ := reflect.TypeOf(types.VisitEntry{})
+	var tags []string
+	for i := 0; i < typ.NumField(); i++ {
+		if tag := typ.Field(i).Tag.Get("json"); tag != "-" {
+			tags = append(tags, tag)
+		}
+	}
+	if !reflect.DeepEqual(tags, export.Columns) {
+		t.Errorf("columns %v do not match JSON fields %v", export.Columns, tags)
+	}
+}
+
+func TestExport_Empty(t *testing.T) {
+	tests := map[export.Format]string{
+		export.JSON:   "[]\n",
+		export.NDJSON: "",
+		export.CSV:    strings.Join(export.Columns, ",") + "\r\n",
+	}
+	for format, want := range tests {
+		var buf bytes.Buffer
+		if err := export.Write(&buf, nil, format); err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if buf.String() != want {
+			t.Errorf("%s: expected %q, got %q", format, want, buf.String())
+		}
+	}
+
+	if _, err := export.NewEncoder(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
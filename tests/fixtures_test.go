@@ -0,0 +1,187 @@
+package parse_test
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// chromeVisit is a row for a fixture Chrome History database.
+type chromeVisit struct {
+	url       string
+	title     string
+	visitTime int64 // microseconds since 1601-01-01 UTC
+
+	// Optional visit metadata
+	transition int64
+	fromVisit  int64
+	duration   int64 // microseconds
+	typed      bool
+}
+
+// createChromeHistory writes a Chrome History database at path holding visits.
+func createChromeHistory(t *testing.T, path string, visits []chromeVisit) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE urls (id INTEGER PRIMARY KEY, url LONGVARCHAR, title LONGVARCHAR,
+			visit_count INTEGER DEFAULT 0 NOT NULL, typed_count INTEGER DEFAULT 0 NOT NULL,
+			last_visit_time INTEGER NOT NULL DEFAULT 0, hidden INTEGER DEFAULT 0 NOT NULL);
+		CREATE TABLE visits (id INTEGER PRIMARY KEY, url INTEGER NOT NULL, visit_time INTEGER NOT NULL,
+			from_visit INTEGER, transition INTEGER DEFAULT 0 NOT NULL, segment_id INTEGER,
+			visit_duration INTEGER DEFAULT 0 NOT NULL);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range visits {
+		var urlID int64
+		err := db.QueryRow(`SELECT id FROM urls WHERE url = ?`, v.url).Scan(&urlID)
+		if err == sql.ErrNoRows {
+			res, err := db.Exec(`INSERT INTO urls (url, title) VALUES (?, ?)`, v.url, v.title)
+			if err != nil {
+				t.Fatal(err)
+			}
+			urlID, _ = res.LastInsertId()
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = db.Exec(`INSERT INTO visits (url, visit_time, transition, from_visit, visit_duration)
+			VALUES (?, ?, ?, ?, ?)`, urlID, v.visitTime, v.transition, v.fromVisit, v.duration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec(`UPDATE urls SET visit_count = visit_count + 1, typed_count = typed_count + ?,
+			last_visit_time = MAX(last_visit_time, ?) WHERE id = ?`, v.typed, v.visitTime, urlID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+// safariVisit is a row for a fixture Safari History.db database.
+type safariVisit struct {
+	url       string
+	title     string  // empty is stored as NULL
+	visitTime float64 // seconds since 2001-01-01 UTC
+}
+
+// createSafariHistory writes a Safari History.db database at path holding visits.
+func createSafariHistory(t *testing.T, path string, visits []safariVisit) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE history_items (id INTEGER PRIMARY KEY AUTOINCREMENT, url TEXT NOT NULL UNIQUE,
+			domain_expansion TEXT NULL, visit_count INTEGER NOT NULL);
+		CREATE TABLE history_visits (id INTEGER PRIMARY KEY AUTOINCREMENT,
+			history_item INTEGER NOT NULL REFERENCES history_items(id) ON DELETE CASCADE,
+			visit_time REAL NOT NULL, title TEXT NULL, load_successful BOOLEAN NOT NULL DEFAULT 1,
+			redirect_source INTEGER NULL UNIQUE, redirect_destination INTEGER NULL UNIQUE);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range visits {
+		_, err := db.Exec(`INSERT INTO history_items (url, visit_count) VALUES (?, 1)
+			ON CONFLICT(url) DO UPDATE SET visit_count = visit_count + 1`, v.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var title any
+		if v.title != "" {
+			title = v.title
+		}
+		_, err = db.Exec(`INSERT INTO history_visits (history_item, visit_time, title)
+			SELECT id, ?, ? FROM history_items WHERE url = ?`, v.visitTime, title, v.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+// firefoxVisit is a row for a fixture Firefox places.sqlite database.
+type firefoxVisit struct {
+	url       string
+	title     string // empty is stored as NULL
+	visitDate int64  // microseconds since the Unix epoch
+
+	// Optional visit metadata
+	visitType int64 // defaults to 1 (link)
+	fromVisit int64
+}
+
+// createFirefoxHistory writes a Firefox places.sqlite database at path holding visits.
+func createFirefoxHistory(t *testing.T, path string, visits []firefoxVisit) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE moz_places (id INTEGER PRIMARY KEY, url LONGVARCHAR, title LONGVARCHAR,
+			rev_host LONGVARCHAR, visit_count INTEGER DEFAULT 0, hidden INTEGER DEFAULT 0 NOT NULL,
+			typed INTEGER DEFAULT 0 NOT NULL, frecency INTEGER DEFAULT -1 NOT NULL,
+			last_visit_date INTEGER);
+		CREATE TABLE moz_historyvisits (id INTEGER PRIMARY KEY, from_visit INTEGER,
+			place_id INTEGER, visit_date INTEGER, visit_type INTEGER, session INTEGER);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range visits {
+		var title any
+		if v.title != "" {
+			title = v.title
+		}
+		var placeID int64
+		err := db.QueryRow(`SELECT id FROM moz_places WHERE url = ?`, v.url).Scan(&placeID)
+		if err == sql.ErrNoRows {
+			res, err := db.Exec(`INSERT INTO moz_places (url, title) VALUES (?, ?)`, v.url, title)
+			if err != nil {
+				t.Fatal(err)
+			}
+			placeID, _ = res.LastInsertId()
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		visitType := v.visitType
+		if visitType == 0 {
+			visitType = 1
+		}
+		_, err = db.Exec(`INSERT INTO moz_historyvisits (place_id, visit_date, visit_type, from_visit)
+			VALUES (?, ?, ?, ?)`, placeID, v.visitDate, visitType, v.fromVisit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec(`UPDATE moz_places SET typed = 1 WHERE id = ? AND ? = 2`, placeID, visitType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec(`UPDATE moz_places SET visit_count = visit_count + 1,
+			last_visit_date = MAX(COALESCE(last_visit_date, 0), ?) WHERE id = ?`, v.visitDate, placeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
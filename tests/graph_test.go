@@ -0,0 +1,159 @@
+package parse_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/graph"
+	"github.com/akshatsrivastava11/Histograph/internals/render"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func graphEntries() []types.VisitEntry {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	visit := func(browser string, id, referrer int64, url, title string) types.VisitEntry {
+		return types.VisitEntry{URL: url, Title: title, VisitTime: base.Add(time.Duration(id) * time.Minute),
+			Browser: browser, Profile: "Default", ProfileDir: "Default", VisitID: id, ReferrerVisitID: referrer,
+			Transition: types.TransitionLink}
+	}
+	search := visit("Chrome", 1, 0, "https://www.google.com/search?q=raft", "raft - Google Search")
+	search.Transition = types.TransitionTyped
+	otherProfile := visit("Chrome", 2, 1, "https://other.example/", "Other")
+	otherProfile.ProfileDir = "Profile 1"
+	return []types.VisitEntry{
+		search,
+		visit("Chrome", 2, 1, "https://en.wikipedia.org/wiki/Raft", "Raft - Wikipedia"),
+		visit("Chrome", 3, 2, "https://raft.github.io/", "The Raft Consensus Algorithm"),
+		visit("Chrome", 4, 3, "https://raft.github.io/raft.pdf", "raft.pdf"),
+		visit("Chrome", 5, 1, "https://raft.github.io/", "The Raft Consensus Algorithm"),
+		// Same visit IDs in another browser must not be linked to Chrome's.
+		visit("Firefox", 2, 1, "https://example.com/", "Example"),
+		// Nor in another Chrome profile that shares the display name.
+		otherProfile,
+		// Referrer outside the loaded entries
+		visit("Chrome", 7, 6, "https://news.example/", "News"),
+	}
+}
+
+func TestGraph_Edges(t *testing.T) {
+	g := graph.Build(graphEntries())
+
+	domains := g.Edges(graph.Domains)
+	want := []graph.Edge{
+		{From: "google.com", To: "en.wikipedia.org", Weight: 1},
+		{From: "google.com", To: "raft.github.io", Weight: 1},
+		{From: "en.wikipedia.org", To: "raft.github.io", Weight: 1},
+	}
+	// Heaviest first, then by name
+	want[0], want[1], want[2] = want[2], want[0], want[1]
+	if len(domains) != len(want) {
+		t.Fatalf("expected domain edges %v, got %v", want, domains)
+	}
+	for i := range want {
+		if domains[i] != want[i] {
+			t.Fatalf("expected domain edges %v, got %v", want, domains)
+		}
+	}
+
+	pages := g.Edges(graph.Pages)
+	if len(pages) != 4 {
+		t.Fatalf("expected 4 page edges, got %v", pages)
+	}
+	for _, e := range pages {
+		if e.To == "https://example.com/" || e.To == "https://other.example/" || e.To == "https://news.example/" {
+			t.Errorf("unexpected edge %v", e)
+		}
+	}
+}
+
+func TestGraph_ChainAndNext(t *testing.T) {
+	entries := graphEntries()
+	g := graph.Build(entries)
+
+	chain := g.Chain(entries[3])
+	var urls []string
+	for _, e := range chain {
+		urls = append(urls, e.URL)
+	}
+	want := "https://www.google.com/search?q=raft https://en.wikipedia.org/wiki/Raft https://raft.github.io/ https://raft.github.io/raft.pdf"
+	if strings.Join(urls, " ") != want {
+		t.Errorf("unexpected chain %v", urls)
+	}
+
+	next := g.Next(entries[0])
+	if len(next) != 2 || next[0].VisitID != 2 || next[1].VisitID != 5 {
+		t.Errorf("unexpected next visits %v", next)
+	}
+
+	// A referrer cycle must not loop forever.
+	cyclic := []types.VisitEntry{
+		{URL: "https://a.example/", Browser: "Chrome", VisitID: 1, ReferrerVisitID: 2},
+		{URL: "https://b.example/", Browser: "Chrome", VisitID: 2, ReferrerVisitID: 1},
+	}
+	if n := len(graph.Build(cyclic).Chain(cyclic[0])); n != 2 {
+		t.Errorf("expected a chain of 2 visits, got %d", n)
+	}
+}
+
+func TestGraph_Export(t *testing.T) {
+	edges := []graph.Edge{
+		{From: "a.example", To: `b"c.example`, Weight: 3},
+		{From: "b\"c.example", To: "a.example", Weight: 1},
+	}
+
+	var dot bytes.Buffer
+	if err := graph.WriteDOT(&dot, edges); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"digraph histograph {", `"a.example" -> "b\"c.example" [weight=3`, "}\n"} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT output does not contain %q:\n%s", want, dot.String())
+		}
+	}
+
+	var ml bytes.Buffer
+	if err := graph.WriteGraphML(&ml, edges); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Nodes []struct {
+			ID   string `xml:"id,attr"`
+			Data string `xml:"data"`
+		} `xml:"graph>node"`
+		Edges []struct {
+			Source string `xml:"source,attr"`
+			Target string `xml:"target,attr"`
+			Data   string `xml:"data"`
+		} `xml:"graph>edge"`
+	}
+	if err := xml.Unmarshal(ml.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid GraphML: %v\n%s", err, ml.String())
+	}
+	if len(doc.Nodes) != 2 || doc.Nodes[1].Data != `b"c.example` {
+		t.Errorf("unexpected nodes %+v", doc.Nodes)
+	}
+	if len(doc.Edges) != 2 || doc.Edges[0].Source != "n0" || doc.Edges[0].Target != "n1" || doc.Edges[0].Data != "3" {
+		t.Errorf("unexpected edges %+v", doc.Edges)
+	}
+}
+
+func TestTrailView(t *testing.T) {
+	var m tea.Model = render.NewChromeHistoryModel(graphEntries(), render.ViewerOptions{Location: time.UTC}, 120, 60)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("4")})
+	// Details lists the most recent first: news, raft.github.io, then raft.pdf
+	for i := 0; i < 2; i++ {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+
+	view := m.View()
+	for _, want := range []string{"How did I get here?", "raft - Google Search", "└─ Raft - Wikipedia", "▶ raft.pdf", "◀ you are here"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("trail does not contain %q:\n%s", want, view)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package parse_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/render"
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestActivityHeatmap(t *testing.T) {
+	// 2025-01-06 is a Monday
+	monday := time.Date(2025, 1, 6, 23, 30, 0, 0, time.UTC)
+	entries := []types.VisitEntry{
+		{URL: "https://github.com/a", VisitTime: monday},
+		{URL: "https://github.com/b", VisitTime: monday.Add(10 * time.Minute)},
+		{URL: "https://news.example/", VisitTime: monday.Add(time.Hour)},
+	}
+
+	h := stats.ActivityHeatmap(entries, time.UTC, "")
+	if h[time.Monday][23] != 2 || h[time.Tuesday][0] != 1 || h.Total() != 3 {
+		t.Errorf("unexpected UTC counts: Mon 23h %d, Tue 0h %d, total %d", h[time.Monday][23], h[time.Tuesday][0], h.Total())
+	}
+	if count, day, hour := h.Max(); count != 2 || day != time.Monday || hour != 23 {
+		t.Errorf("unexpected peak %d on %v at %d", count, day, hour)
+	}
+
+	// Grouped in the viewer's time zone, the late visits move to the next day.
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h = stats.ActivityHeatmap(entries, berlin, "")
+	if h[time.Tuesday][0] != 2 || h[time.Tuesday][1] != 1 {
+		t.Errorf("unexpected Berlin counts: Tue 0h %d, Tue 1h %d", h[time.Tuesday][0], h[time.Tuesday][1])
+	}
+
+	h = stats.ActivityHeatmap(entries, time.UTC, "news.example")
+	if h.Total() != 1 || h[time.Tuesday][0] != 1 {
+		t.Errorf("expected only the news.example visit, got total %d", h.Total())
+	}
+}
+
+func TestHeatmapView(t *testing.T) {
+	monday := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	entries := []types.VisitEntry{
+		{URL: "https://github.com/a", VisitCount: 5, VisitTime: monday},
+		{URL: "https://news.example/", VisitCount: 1, VisitTime: monday.Add(24 * time.Hour)},
+	}
+
+	var m tea.Model = render.NewChromeHistoryModel(entries, render.ViewerOptions{Location: time.UTC}, 120, 40)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("6")})
+	view := m.View()
+	if !strings.Contains(view, "All domains") || !strings.Contains(view, "2 visits") {
+		t.Fatalf("expected the heatmap of every domain, got:\n%s", view)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	view = m.View()
+	if !strings.Contains(view, "github.com") || !strings.Contains(view, "busiest: Mon 09:00 (1 visits)") {
+		t.Fatalf("expected the heatmap of the top domain, got:\n%s", view)
+	}
+}
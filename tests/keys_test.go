@@ -0,0 +1,33 @@
+package parse_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// namedKeys are the keys key accepts by name rather than as typed text.
+var namedKeys = map[string]tea.KeyType{
+	"enter": tea.KeyEnter,
+	"esc":   tea.KeyEsc,
+	"up":    tea.KeyUp,
+	"down":  tea.KeyDown,
+	"left":  tea.KeyLeft,
+	"right": tea.KeyRight,
+}
+
+// key returns the KeyMsg of pressing the named key, or of typing s.
+func key(s string) tea.KeyMsg {
+	if typ, ok := namedKeys[s]; ok {
+		return tea.KeyMsg{Type: typ}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// press sends msg to *m and runs the commands it returns, as the program would.
+func press(t *testing.T, m *tea.Model, msg tea.KeyMsg) {
+	t.Helper()
+	var cmd tea.Cmd
+	*m, cmd = (*m).Update(msg)
+	*m = runCmd(t, *m, cmd)
+}
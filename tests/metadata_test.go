@@ -0,0 +1,124 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+func TestParse_ChromeVisitMetadata(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "History")
+	createChromeHistory(t, historyPath, []chromeVisit{
+		{url: "https://search.example/", title: "Search", visitTime: 13379755200000000,
+			transition: 0x30000001, typed: true, duration: 2500000},
+		{url: "https://result.example/", title: "Result", visitTime: 13379755201000000,
+			transition: 0, fromVisit: 1},
+	})
+	t.Setenv("CHROME_HISTORY_PATH", historyPath)
+
+	src, _ := parse.Lookup("Chrome")
+	entries, err := parse.Parse(src, parse.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	result, search := entries[0], entries[1]
+	if search.Browser != "Chrome" || search.Profile != "Default" {
+		t.Errorf("unexpected provenance: %s/%s", search.Browser, search.Profile)
+	}
+	if search.VisitID != 1 || search.URLID != 1 || search.TypedCount != 1 {
+		t.Errorf("unexpected ids or typed count: %+v", search)
+	}
+	if search.Transition != types.TransitionTyped {
+		t.Errorf("expected qualifiers to be ignored and typed transition, got %q", search.Transition)
+	}
+	if search.Duration != 2500*time.Millisecond {
+		t.Errorf("expected 2.5s duration, got %s", search.Duration)
+	}
+	if result.ReferrerVisitID != search.VisitID || result.Transition != types.TransitionLink {
+		t.Errorf("expected link from visit %d, got %+v", search.VisitID, result)
+	}
+}
+
+// Chrome leaves the title NULL for some URLs, which must not fail the profile.
+func TestParse_ChromeNullTitle(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "History")
+	db := createChromeHistory(t, historyPath, []chromeVisit{
+		{url: "https://untitled.example/", visitTime: 13379755200000000},
+		{url: "https://titled.example/", title: "Titled", visitTime: 13379755201000000},
+	})
+	if _, err := db.Exec(`UPDATE urls SET title = NULL WHERE url = 'https://untitled.example/'`); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CHROME_HISTORY_PATH", historyPath)
+
+	src, _ := parse.Lookup("Chrome")
+	entries, err := parse.Parse(src, parse.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Title != "Titled" || entries[1].Title != "" {
+		t.Errorf("expected both visits with the NULL title empty, got %+v", entries)
+	}
+}
+
+func TestParse_FirefoxVisitMetadata(t *testing.T) {
+	placesPath := filepath.Join(t.TempDir(), "places.sqlite")
+	createFirefoxHistory(t, placesPath, []firefoxVisit{
+		{url: "https://old.example/", visitDate: 1735281600000000, visitType: 2},
+		{url: "https://new.example/", visitDate: 1735281601000000, visitType: 5, fromVisit: 1},
+	})
+	t.Setenv("FIREFOX_HISTORY_PATH", placesPath)
+
+	src, _ := parse.Lookup("Firefox")
+	entries, err := parse.Parse(src, parse.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	redirect, typed := entries[0], entries[1]
+	if typed.Transition != types.TransitionTyped || typed.TypedCount != 1 {
+		t.Errorf("unexpected typed visit: %+v", typed)
+	}
+	if redirect.Transition != types.TransitionRedirect || redirect.ReferrerVisitID != typed.VisitID {
+		t.Errorf("unexpected redirect visit: %+v", redirect)
+	}
+	if redirect.Browser != "Firefox" || redirect.URLID != 2 {
+		t.Errorf("unexpected provenance or url id: %+v", redirect)
+	}
+}
+
+func TestVisitEntry_JSONTags(t *testing.T) {
+	data, err := json.Marshal(types.VisitEntry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"url", "title", "visit_count", "visit_time", "browser", "profile", "visit_id",
+		"url_id", "typed_count", "transition", "referrer_visit_id", "duration",
+	}
+	if len(fields) != len(want) {
+		t.Errorf("expected %d fields, got %v", len(want), fields)
+	}
+	for _, key := range want {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("missing JSON field %q", key)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package parse_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+)
+
+// webkit converts a time to Chrome's microseconds since 1601-01-01.
+func webkit(t time.Time) int64 {
+	return t.UnixMicro() + 11644473600*1000000
+}
+
+func TestParse_Options(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 12, 0, 0, 0, time.UTC) }
+
+	historyPath := filepath.Join(t.TempDir(), "History")
+	createChromeHistory(t, historyPath, []chromeVisit{
+		{url: "https://github.com/golang/go", title: "Go", visitTime: webkit(day(1))},
+		{url: "https://gist.github.com/x", title: "Gist", visitTime: webkit(day(2))},
+		{url: "https://notgithub.com/", title: "Impostor", visitTime: webkit(day(3))},
+		{url: "http://GitHub.com:8080/", title: "Port", visitTime: webkit(day(4))},
+		{url: "https://www.google.com/url?q=https://github.com/golang/go", title: "Redirect", visitTime: webkit(day(4).Add(-time.Hour))},
+		{url: "https://news.ycombinator.com/", title: "HN", visitTime: webkit(day(5))},
+		{url: "https://github.com/", title: "Home", visitTime: webkit(day(6))},
+	})
+	t.Setenv("CHROME_HISTORY_PATH", historyPath)
+	src, _ := parse.Lookup("Chrome")
+
+	tests := []struct {
+		name string
+		opts parse.Options
+		want []string
+	}{
+		{"no options", parse.Options{}, []string{"Home", "HN", "Port", "Redirect", "Impostor", "Gist", "Go"}},
+		{"limit", parse.Options{Limit: 2}, []string{"Home", "HN"}},
+		{"since", parse.Options{Since: day(5)}, []string{"Home", "HN"}},
+		{"until is exclusive", parse.Options{Until: day(2)}, []string{"Go"}},
+		{"window", parse.Options{Since: day(2), Until: day(5)}, []string{"Port", "Redirect", "Impostor", "Gist"}},
+		{"domain", parse.Options{Domain: "github.com"}, []string{"Home", "Port", "Gist", "Go"}},
+		{"domain and limit", parse.Options{Domain: "github.com", Limit: 1, Until: day(6)}, []string{"Port"}},
+		{"domain wildcards are literal", parse.Options{Domain: "git_ub.com"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := parse.Parse(src, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Title)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
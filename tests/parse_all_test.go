@@ -0,0 +1,68 @@
+package parse_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+)
+
+// isolateSources points every source at an empty home directory so only the
+// fixtures a test sets up are detected.
+func isolateSources(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	for _, envVar := range []string{
+		"CHROME_HISTORY_PATH", "CHROMIUM_HISTORY_PATH", "BRAVE_HISTORY_PATH", "EDGE_HISTORY_PATH",
+		"VIVALDI_HISTORY_PATH", "OPERA_HISTORY_PATH", "FIREFOX_HISTORY_PATH", "LIBREWOLF_HISTORY_PATH",
+		"WATERFOX_HISTORY_PATH", "ZEN_HISTORY_PATH", "FLOORP_HISTORY_PATH", "SAFARI_HISTORY_PATH",
+	} {
+		t.Setenv(envVar, "")
+	}
+}
+
+func TestParseAll_MergesDetectedSources(t *testing.T) {
+	isolateSources(t)
+	dir := t.TempDir()
+
+	chromePath := filepath.Join(dir, "History")
+	createChromeHistory(t, chromePath, []chromeVisit{
+		{url: "https://a.example/", title: "Chrome old", visitTime: 13379755200000000},
+		{url: "https://b.example/", title: "Chrome new", visitTime: 13379755202000000},
+	})
+	t.Setenv("CHROME_HISTORY_PATH", chromePath)
+
+	firefoxPath := filepath.Join(dir, "places.sqlite")
+	createFirefoxHistory(t, firefoxPath, []firefoxVisit{
+		{url: "https://c.example/", title: "Firefox middle", visitDate: 1735281601000000},
+	})
+	t.Setenv("FIREFOX_HISTORY_PATH", firefoxPath)
+
+	entries, err := parse.ParseAll(parse.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct{ title, browser string }{
+		{"Chrome new", "Chrome"},
+		{"Firefox middle", "Firefox"},
+		{"Chrome old", "Chrome"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), entries)
+	}
+	for i, w := range want {
+		if entries[i].Title != w.title || entries[i].Browser != w.browser {
+			t.Errorf("entry %d: expected %s from %s, got %s from %s",
+				i, w.title, w.browser, entries[i].Title, entries[i].Browser)
+		}
+	}
+}
+
+func TestParseAll_NothingDetected(t *testing.T) {
+	isolateSources(t)
+
+	if _, err := parse.ParseAll(parse.Options{}); err == nil {
+		t.Error("expected an error when no browser is detected")
+	}
+}
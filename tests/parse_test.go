@@ -2,6 +2,7 @@ package parse_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/akshatsrivastava11/Histograph/internals/parse"
@@ -33,4 +34,50 @@ func TestGetFirefoxHistoryPath_EnvOverride(t *testing.T) {
 	}
 }
 
+func TestLookup_BuiltinSources(t *testing.T) {
+	for _, name := range []string{"Chrome", "Firefox"} {
+		src, ok := parse.Lookup(name)
+		if !ok {
+			t.Fatalf("expected %s to be registered", name)
+		}
+		if src.Name() != name {
+			t.Errorf("expected source named %s, got %s", name, src.Name())
+		}
+	}
+
+	if _, ok := parse.Lookup("Netscape"); ok {
+		t.Errorf("expected Netscape not to be registered")
+	}
+}
+
+func TestChromiumSources_DetectEnvOverride(t *testing.T) {
+	browsers := map[string]string{
+		"Chromium": "CHROMIUM_HISTORY_PATH",
+		"Brave":    "BRAVE_HISTORY_PATH",
+		"Edge":     "EDGE_HISTORY_PATH",
+		"Vivaldi":  "VIVALDI_HISTORY_PATH",
+		"Opera":    "OPERA_HISTORY_PATH",
+	}
+
+	for name, envVar := range browsers {
+		src, ok := parse.Lookup(name)
+		if !ok {
+			t.Fatalf("expected %s to be registered", name)
+		}
+
+		missing := filepath.Join(t.TempDir(), "History")
+		t.Setenv(envVar, missing)
+		if src.Detect() {
+			t.Errorf("%s: expected missing history file not to be detected", name)
+		}
+
+		if err := os.WriteFile(missing, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if !src.Detect() {
+			t.Errorf("%s: expected history file at %s to be detected", name, missing)
+		}
+	}
+}
+
 // Note: OS-specific path tests would require refactoring the parse package to export the path logic as a testable function and/or allow injection of runtime.GOOS and home directory. This is a basic test for env override logic.
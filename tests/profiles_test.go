@@ -0,0 +1,179 @@
+package parse_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChromeProfiles_LocalState(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("profile roots are only faked for Linux")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CHROME_HISTORY_PATH", "")
+
+	root := filepath.Join(home, ".config", "google-chrome")
+	writeFile(t, filepath.Join(root, "Local State"), `{"profile":{"info_cache":{
+		"Default":{"name":"Personal"},
+		"Profile 1":{"name":"Work"},
+		"Profile 2":{"name":"Never opened"}}}}`)
+	writeFile(t, filepath.Join(root, "Default", "History"), "")
+	writeFile(t, filepath.Join(root, "Profile 1", "History"), "")
+
+	src, _ := parse.Lookup("Chrome")
+	profiles, err := src.Profiles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("expected 2 profiles with a History file, got %+v", profiles)
+	}
+	if profiles[0].Name != "Personal" || !profiles[0].Default {
+		t.Errorf("expected default profile Personal first, got %+v", profiles[0])
+	}
+	if profiles[1].Name != "Work" || profiles[1].Dir != "Profile 1" {
+		t.Errorf("expected Work profile in Profile 1, got %+v", profiles[1])
+	}
+}
+
+func TestProfiles_EnvOverride(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "Profile 1", "History")
+	t.Setenv("CHROME_HISTORY_PATH", historyPath)
+
+	src, _ := parse.Lookup("Chrome")
+	profiles, err := src.Profiles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Dir names the directory, as for profiles found on disk, not its full path.
+	if len(profiles) != 1 || profiles[0].Dir != "Profile 1" || profiles[0].Path != historyPath {
+		t.Errorf("expected one profile in Profile 1, got %+v", profiles)
+	}
+}
+
+func TestFirefoxProfiles_ProfilesINI(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("profile roots are only faked for Linux")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("FIREFOX_HISTORY_PATH", "")
+
+	root := filepath.Join(home, ".mozilla", "firefox")
+	writeFile(t, filepath.Join(root, "profiles.ini"), `
+[Profile1]
+Name=work
+IsRelative=1
+Path=w0rk.work
+
+[Profile0]
+Name=default-release
+IsRelative=1
+Path=abcd.default-release
+Default=1
+
+[Install4F96D1932A9F858E]
+Default=abcd.default-release
+Locked=1
+
+[General]
+StartWithLastProfile=1
+Version=2
+`)
+	writeFile(t, filepath.Join(root, "abcd.default-release", "places.sqlite"), "")
+	writeFile(t, filepath.Join(root, "w0rk.work", "places.sqlite"), "")
+
+	src, _ := parse.Lookup("Firefox")
+	profiles, err := src.Profiles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("expected 2 profiles, got %+v", profiles)
+	}
+	if profiles[0].Name != "default-release" || !profiles[0].Default {
+		t.Errorf("expected default-release first, got %+v", profiles[0])
+	}
+	if profiles[1].Name != "work" {
+		t.Errorf("expected work profile, got %+v", profiles[1])
+	}
+
+	path, err := parse.GetFirefoxHistoryPath()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != profiles[0].Path {
+		t.Errorf("expected default profile path %s, got %s", profiles[0].Path, path)
+	}
+}
+
+func TestGeckoForks_ProfileRoots(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("profile roots are only faked for Linux")
+	}
+
+	forks := map[string]string{
+		"LibreWolf": ".librewolf",
+		"Waterfox":  ".waterfox",
+		"Zen":       ".zen",
+		"Floorp":    ".floorp",
+	}
+
+	for name, rootDir := range forks {
+		t.Run(name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+
+			src, ok := parse.Lookup(name)
+			if !ok {
+				t.Fatalf("expected %s to be registered", name)
+			}
+			if src.Detect() {
+				t.Fatalf("expected %s not to be detected without a profile", name)
+			}
+
+			root := filepath.Join(home, rootDir)
+			writeFile(t, filepath.Join(root, "profiles.ini"), `
+[Profile0]
+Name=Default (release)
+IsRelative=1
+Path=Profiles/x1y2.Default (release)
+Default=1
+`)
+			placesPath := filepath.Join(root, "Profiles", "x1y2.Default (release)", "places.sqlite")
+			if err := os.MkdirAll(filepath.Dir(placesPath), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			createFirefoxHistory(t, placesPath, []firefoxVisit{
+				{url: "https://codeberg.org/", title: "Codeberg", visitDate: 1735281600000000},
+				{url: "https://example.org/", visitDate: 1735281600000001},
+			})
+
+			if !src.Detect() {
+				t.Fatalf("expected %s to be detected", name)
+			}
+			entries, err := parse.Parse(src, parse.Options{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(entries) != 2 || entries[0].URL != "https://example.org/" || entries[1].Title != "Codeberg" {
+				t.Errorf("unexpected entries: %+v", entries)
+			}
+		})
+	}
+}
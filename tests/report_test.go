@@ -0,0 +1,61 @@
+package parse_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/report"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+func TestReport_HTML(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 30, 0, 0, time.UTC)
+	entries := []types.VisitEntry{
+		{URL: "https://go.dev/doc/", Title: "Go <script>alert(1)</script>", VisitCount: 3, VisitTime: base, Browser: "Chrome"},
+		{URL: "https://go.dev/blog/", Title: "Blog", VisitCount: 1, VisitTime: base.Add(49 * time.Hour), Browser: "Firefox"},
+	}
+
+	var buf bytes.Buffer
+	err := report.Write(&buf, entries, report.Options{Title: "Team retro", Location: time.UTC, Now: base})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		"<title>Team retro</title>",
+		"Go &lt;script&gt;alert(1)&lt;/script&gt;",
+		`data-domain="go.dev"`,
+		"2025-01-01: 1 visits",
+		"2025-01-02: 0 visits", // days without visits are charted too
+		"2025-01-03: 1 visits",
+		"09:00: 1 visits",
+		"Chrome 1 • Firefox 1",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+	if strings.Contains(html, "<script>alert") {
+		t.Error("entry titles must be escaped")
+	}
+
+	// The report must not load anything: no external scripts, styles or images.
+	external := regexp.MustCompile(`<(script|link|img)[^>]+(src|href)=`)
+	if m := external.FindString(html); m != "" {
+		t.Errorf("report references an external resource: %s", m)
+	}
+}
+
+func TestReport_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := report.Write(&buf, nil, report.Options{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No history entries matched.") {
+		t.Error("expected an empty report notice")
+	}
+}
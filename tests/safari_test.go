@@ -0,0 +1,74 @@
+package parse_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+)
+
+func TestGetSafariHistoryPath_EnvOverride(t *testing.T) {
+	t.Setenv("SAFARI_HISTORY_PATH", "/tmp/fake_safari_history")
+
+	path, err := parse.GetSafariHistoryPath()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/tmp/fake_safari_history" {
+		t.Errorf("expected /tmp/fake_safari_history, got %s", path)
+	}
+}
+
+func TestCoreDataTimeToUnix(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds float64
+		want    time.Time
+	}{
+		{"core data epoch", 0, time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"unix epoch", -978307200, time.Unix(0, 0).UTC()},
+		{"fractional seconds", 757000000.25, time.Date(2024, 12, 27, 13, 46, 40, 250000000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parse.CoreDataTimeToUnix(tt.seconds)
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParse_SafariFixture(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "History.db")
+	createSafariHistory(t, historyPath, []safariVisit{
+		{url: "https://www.apple.com/", title: "Apple", visitTime: 757000000},
+		{url: "https://webkit.org/", visitTime: 757000100.5},
+		{url: "https://www.apple.com/", title: "Apple", visitTime: 757000200},
+	})
+	t.Setenv("SAFARI_HISTORY_PATH", historyPath)
+
+	src, ok := parse.Lookup("Safari")
+	if !ok {
+		t.Fatal("expected Safari to be registered")
+	}
+	if !src.Detect() {
+		t.Fatal("expected fixture database to be detected")
+	}
+
+	entries, err := parse.Parse(src, parse.Options{Since: parse.CoreDataTimeToUnix(757000050)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if entries[0].URL != "https://www.apple.com/" || entries[0].VisitCount != 2 {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Title != "" || !entries[1].VisitTime.Equal(parse.CoreDataTimeToUnix(757000100.5)) {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+}
@@ -0,0 +1,70 @@
+//go:build sqlite_fts5
+
+package parse_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/archive"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+// These tests cover the FTS5 index, which is only built with -tags sqlite_fts5.
+
+func TestArchive_SearchFullText(t *testing.T) {
+	a := openArchive(t)
+	if !a.FullText() {
+		t.Fatal("expected the FTS5 index with -tags sqlite_fts5")
+	}
+	base := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	// The older the visit, the better its match, so ranking cannot come from recency.
+	_, err := a.Ingest([]types.VisitEntry{
+		{URL: "https://books.example/42", Title: "The Rust Programming Language",
+			VisitTime: base, Browser: "Chrome", Profile: "Default", VisitID: 1},
+		{URL: "https://rust.example/", Title: "Home",
+			VisitTime: base.Add(time.Hour), Browser: "Chrome", Profile: "Default", VisitID: 2},
+		{URL: "https://blog.example/posts/rust", Title: "Posts",
+			VisitTime: base.Add(2 * time.Hour), Browser: "Chrome", Profile: "Default", VisitID: 3},
+		{URL: "https://docs.example/", Title: "Documentation",
+			VisitTime: base.Add(3 * time.Hour), Browser: "Chrome", Profile: "Default", VisitID: 4},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"title, then domain, then url", "rust",
+			[]string{"https://books.example/42", "https://rust.example/", "https://blog.example/posts/rust"}},
+		{"prefix", "docu", []string{"https://docs.example/"}},
+		// Unlike the substring fallback, words only match from their start.
+		{"not mid-word", "mentation", nil},
+		{"operators are literal", `rust OR "home`, nil},
+		{"every term", "rust home", []string{"https://rust.example/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := a.Search(tt.text, archive.Query{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.URL)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package parse_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/archive"
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+	"github.com/akshatsrivastava11/Histograph/internals/render"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+func TestArchive_Search(t *testing.T) {
+	a := openArchive(t)
+	base := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	_, err := a.Ingest([]types.VisitEntry{
+		{URL: "https://go.dev/doc/", Title: "Documentation - The Go Programming Language",
+			VisitTime: base, Browser: "Chrome", Profile: "Default", VisitID: 1},
+		{URL: "https://example.com/golang-tips", Title: "Tips",
+			VisitTime: base.Add(time.Hour), Browser: "Chrome", Profile: "Default", VisitID: 2},
+		{URL: "https://news.example.org/", Title: "News",
+			VisitTime: base.Add(2 * time.Hour), Browser: "Firefox", Profile: "default-release", VisitID: 1},
+		{URL: "https://go.dev/blog/", Title: "The Go Blog",
+			VisitTime: base.Add(48 * time.Hour), Browser: "Firefox", Profile: "default-release", VisitID: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		text  string
+		query archive.Query
+		want  []string
+	}{
+		// Title hits rank above URL-only hits, even when older.
+		{"title before url", "go", archive.Query{},
+			[]string{"https://go.dev/blog/", "https://go.dev/doc/", "https://example.com/golang-tips"}},
+		{"every term must match", "go blog", archive.Query{}, []string{"https://go.dev/blog/"}},
+		{"domain", "news.example", archive.Query{}, []string{"https://news.example.org/"}},
+		{"case insensitive", "DOCUMENTATION", archive.Query{}, []string{"https://go.dev/doc/"}},
+		{"no match", "rust", archive.Query{}, nil},
+		{"since", "go", archive.Query{Options: parse.Options{Since: base.Add(24 * time.Hour)}},
+			[]string{"https://go.dev/blog/"}},
+		{"browser", "go", archive.Query{Browser: "Chrome"},
+			[]string{"https://go.dev/doc/", "https://example.com/golang-tips"}},
+		{"limit", "go", archive.Query{Options: parse.Options{Limit: 1}}, []string{"https://go.dev/blog/"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := a.Search(tt.text, tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.URL)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestArchive_SearchEmptyQuery(t *testing.T) {
+	a := openArchive(t)
+	if _, err := a.Search("   ", archive.Query{}); err == nil {
+		t.Error("expected an error for an empty query")
+	}
+}
+
+func TestFilterSearch(t *testing.T) {
+	entries := []types.VisitEntry{
+		{URL: "https://raft.github.io/", Title: "Raft Consensus Algorithm"},
+		{URL: "https://www.example.com/distributed", Title: "Notes on Raft"},
+		{URL: "https://news.example.org/", Title: "News"},
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 3},
+		{"raft", 2},
+		{"RAFT consensus", 1},
+		{"example.com", 1},
+		{"raft news", 0},
+	}
+	for _, tt := range tests {
+		if got := render.FilterSearch(entries, tt.query); len(got) != tt.want {
+			t.Errorf("FilterSearch(%q): expected %d entries, got %d", tt.query, tt.want, len(got))
+		}
+	}
+}
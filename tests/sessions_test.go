@@ -0,0 +1,105 @@
+package parse_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/render"
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func sessionEntries() []types.VisitEntry {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	visit := func(id int64, offset time.Duration, url string, referrer int64) types.VisitEntry {
+		return types.VisitEntry{URL: url, Title: url, VisitTime: base.Add(offset),
+			Browser: "Chrome", Profile: "Default", VisitID: id, ReferrerVisitID: referrer}
+	}
+	// Given newest first, as the parsers return them
+	return []types.VisitEntry{
+		visit(6, 5*time.Hour, "https://c.example/", 2), // links from an older session
+		visit(5, 3*time.Hour+50*time.Minute, "https://b.example/2", 4),
+		visit(4, 3*time.Hour, "https://b.example/1", 0),
+		visit(3, 50*time.Minute, "https://go.dev/blog/", 0),
+		visit(2, 10*time.Minute, "https://go.dev/doc/", 1),
+		visit(1, 0, "https://github.com/", 0),
+	}
+}
+
+func TestSessions(t *testing.T) {
+	sessions := stats.Sessions(sessionEntries(), 30*time.Minute)
+
+	var got [][]int64
+	for _, s := range sessions {
+		var ids []int64
+		for _, v := range s.Visits {
+			ids = append(ids, v.VisitID)
+		}
+		got = append(got, ids)
+	}
+	// Visit 3 comes 40 minutes after visit 2 without a referrer, so it starts a
+	// new session. Visit 5 comes 50 minutes after visit 4 but follows a link
+	// from it. Visit 6 links from visit 2, which is not in the latest session.
+	want := [][]int64{{1, 2}, {3}, {4, 5}, {6}}
+	if len(got) != len(want) {
+		t.Fatalf("expected sessions %v, got %v", want, got)
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) {
+			t.Fatalf("expected sessions %v, got %v", want, got)
+		}
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Fatalf("expected sessions %v, got %v", want, got)
+			}
+		}
+	}
+
+	first := sessions[0]
+	if first.Duration() != 10*time.Minute {
+		t.Errorf("expected a 10 minute session, got %v", first.Duration())
+	}
+	if d := first.Domains(); len(d) != 2 || d[0] != "github.com" || d[1] != "go.dev" {
+		t.Errorf("unexpected domains %v", d)
+	}
+
+	// A longer gap joins the first two sessions.
+	if n := len(stats.Sessions(sessionEntries(), time.Hour)); n != 3 {
+		t.Errorf("expected 3 sessions with a one hour gap, got %d", n)
+	}
+	// Visit 5 of another profile with the same display name does not follow
+	// visit 4's link.
+	entries := sessionEntries()
+	entries[1].ProfileDir = "Profile 1"
+	if n := len(stats.Sessions(entries, 30*time.Minute)); n != 5 {
+		t.Errorf("expected 5 sessions with visit 5 in another profile, got %d", n)
+	}
+	if stats.Sessions(nil, 0) != nil {
+		t.Error("expected no sessions without entries")
+	}
+}
+
+func TestSessionsView(t *testing.T) {
+	var m tea.Model = render.NewChromeHistoryModel(sessionEntries(),
+		render.ViewerOptions{Location: time.UTC, SessionGap: 30 * time.Minute}, 120, 40)
+
+	press(t, &m, key("5"))
+	view := m.View()
+	if !strings.Contains(view, "Sessions (4)") || !strings.Contains(view, "▶ Wed Jan 1, 14:00") {
+		t.Fatalf("expected the session list with the newest selected, got:\n%s", view)
+	}
+
+	press(t, &m, key("down"))
+	press(t, &m, key("enter"))
+	view = m.View()
+	if !strings.Contains(view, "Session Wed Jan 1, 12:00 – 12:50") || !strings.Contains(view, "https://b.example/2") {
+		t.Fatalf("expected the second newest session's visits, got:\n%s", view)
+	}
+
+	press(t, &m, key("esc"))
+	if view = m.View(); !strings.Contains(view, "Sessions (4)") {
+		t.Fatalf("expected esc to return to the list, got:\n%s", view)
+	}
+}
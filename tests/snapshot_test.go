@@ -0,0 +1,58 @@
+package parse_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+)
+
+func TestParse_ReadsLockedDatabaseFromSnapshot(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "History")
+	db := createChromeHistory(t, historyPath, []chromeVisit{
+		{url: "https://example.com/", title: "Example", visitTime: 13350000000000000},
+		{url: "https://go.dev/", title: "Go", visitTime: 13350000001000000},
+	})
+	t.Setenv("CHROME_HISTORY_PATH", historyPath)
+
+	// Hold an exclusive lock the way a running browser does.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`PRAGMA locking_mode = EXCLUSIVE`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE urls SET hidden = 0`); err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := os.ReadFile(historyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpBefore, _ := filepath.Glob(filepath.Join(os.TempDir(), "histograph-*"))
+
+	src, _ := parse.Lookup("Chrome")
+	entries, err := parse.Parse(src, parse.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].URL != "https://go.dev/" {
+		t.Errorf("expected most recent visit first, got %s", entries[0].URL)
+	}
+
+	after, err := os.ReadFile(historyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Errorf("live history database was modified")
+	}
+
+	tmpAfter, _ := filepath.Glob(filepath.Join(os.TempDir(), "histograph-*"))
+	if len(tmpAfter) > len(tmpBefore) {
+		t.Errorf("snapshot directory was not cleaned up: %v", tmpAfter)
+	}
+}
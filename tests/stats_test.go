@@ -0,0 +1,72 @@
+package parse_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+func TestDomain(t *testing.T) {
+	tests := map[string]string{
+		"https://www.github.com/foo": "github.com",
+		"http://example.com":         "example.com",
+		"https://docs.google.com/a":  "docs.google.com",
+	}
+	for url, want := range tests {
+		if got := stats.Domain(url); got != want {
+			t.Errorf("Domain(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	entries := []types.VisitEntry{
+		{URL: "https://a.example/x", VisitCount: 3, VisitTime: base.Add(time.Hour), Browser: "Chrome"},
+		{URL: "https://a.example/x", VisitCount: 3, VisitTime: base, Browser: "Chrome"},
+		{URL: "https://www.b.example/", VisitCount: 1, VisitTime: base.Add(2 * time.Hour), Browser: "Firefox"},
+	}
+
+	s := stats.Summarize(entries)
+	if s.Entries != 3 || s.Visits != 7 || s.UniqueURLs != 2 || s.Domains != 2 {
+		t.Errorf("unexpected counts: %+v", s)
+	}
+	if !s.FirstVisit.Equal(base) || !s.LastVisit.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("unexpected visit range %v - %v", s.FirstVisit, s.LastVisit)
+	}
+	if s.Browsers["Chrome"] != 2 || s.Browsers["Firefox"] != 1 {
+		t.Errorf("unexpected browser counts %v", s.Browsers)
+	}
+}
+
+func TestTopSites(t *testing.T) {
+	entries := []types.VisitEntry{
+		{URL: "https://b.example/", Title: "B", VisitCount: 2, Browser: "Chrome"},
+		{URL: "https://a.example/1", Title: "A", VisitCount: 1, Browser: "Chrome"},
+		{URL: "https://a.example/2", VisitCount: 1, Browser: "Firefox"},
+		{URL: "https://c.example/", VisitCount: 5, Browser: "Chrome"},
+	}
+
+	sites := stats.TopSites(entries)
+	var got []string
+	for _, s := range sites {
+		got = append(got, s.Domain)
+	}
+	// a.example and b.example tie on visits and are ordered by domain.
+	want := []string{"c.example", "a.example", "b.example"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	a := sites[1]
+	if a.Visits != 2 || a.Entries != 2 || a.Title != "A" || a.Browsers["Chrome"] != 1 || a.Browsers["Firefox"] != 1 {
+		t.Errorf("unexpected a.example aggregate: %+v", a)
+	}
+}
@@ -0,0 +1,129 @@
+package parse_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akshatsrivastava11/Histograph/internals/archive"
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+func TestArchive_SyncIsIncremental(t *testing.T) {
+	isolateSources(t)
+	historyPath := filepath.Join(t.TempDir(), "Profile 3", "History")
+	if err := os.Mkdir(filepath.Dir(historyPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db := createChromeHistory(t, historyPath, []chromeVisit{
+		{url: "https://a.example/", title: "A", visitTime: 13379755200000000},
+		{url: "https://b.example/", title: "B", visitTime: 13379755201000000},
+	})
+	t.Setenv("CHROME_HISTORY_PATH", historyPath)
+	src, _ := parse.Lookup("Chrome")
+
+	a := openArchive(t)
+	results := a.Sync([]parse.HistorySource{src})
+	if len(results) != 1 || results[0].Err != nil || results[0].Added != 2 {
+		t.Fatalf("unexpected first sync: %+v", results)
+	}
+	if results[0].Browser != "Chrome" || results[0].Profile != "Default" {
+		t.Errorf("unexpected sync target: %+v", results[0])
+	}
+
+	// The mark is kept under the profile's directory, not its renameable name.
+	mark, err := a.HighWaterMark("Chrome", "Profile 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byName, _ := a.HighWaterMark("Chrome", "Default"); !byName.IsZero() {
+		t.Errorf("expected no high-water mark under the display name, got %s", byName)
+	}
+	if !mark.Equal(parse.ChromeTimeToUnix(13379755201000000)) {
+		t.Errorf("expected high-water mark at the newest visit, got %s", mark)
+	}
+
+	// A visit older than the mark is not pulled again, a newer one is.
+	if _, err := db.Exec(`INSERT INTO visits (url, visit_time) VALUES (1, 13379755100000000), (2, 13379755202000000)`); err != nil {
+		t.Fatal(err)
+	}
+	results = a.Sync([]parse.HistorySource{src})
+	if len(results) != 1 || results[0].Err != nil || results[0].Added != 1 {
+		t.Fatalf("unexpected second sync: %+v", results)
+	}
+
+	entries, err := a.Entries(archive.Query{Browser: "Chrome"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("expected 3 archived visits, got %d", len(entries))
+	}
+
+	results = a.Sync([]parse.HistorySource{src})
+	if results[0].Added != 0 {
+		t.Errorf("expected nothing new on a repeated sync, got %d", results[0].Added)
+	}
+}
+
+// Repeated syncs re-read the visits at the high-water mark, so re-ingesting
+// unchanged visits must leave the search index alone while retitled ones are
+// re-indexed.
+func TestArchive_IngestReindexesOnlyChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.db")
+	a, err := archive.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	entries := []types.VisitEntry{
+		{URL: "https://a.example/", Title: "Draft", VisitCount: 1, VisitTime: base, Browser: "Chrome", Profile: "Default", VisitID: 1},
+		{URL: "https://b.example/", Title: "Other", VisitCount: 1, VisitTime: base.Add(time.Minute), Browser: "Chrome", Profile: "Default", VisitID: 2},
+	}
+	if _, err := a.Ingest(entries); err != nil {
+		t.Fatal(err)
+	}
+
+	// The FTS5 shadow table grows with every write to the index. It only
+	// exists when the archive was built with FTS5.
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	indexWrites := func() int {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM visits_fts_data`).Scan(&n); err != nil {
+			return -1
+		}
+		return n
+	}
+
+	before := indexWrites()
+	entries[1].VisitCount = 3
+	if _, err := a.Ingest(entries); err != nil {
+		t.Fatal(err)
+	}
+	if after := indexWrites(); after != before {
+		t.Errorf("expected unchanged titles to leave the index alone, it went from %d to %d rows", before, after)
+	}
+
+	entries[0].Title = "Published"
+	if _, err := a.Ingest(entries); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := a.Search("published", archive.Query{}); len(got) != 1 {
+		t.Errorf("expected the new title to be searchable, got %+v", got)
+	}
+	if got, _ := a.Search("draft", archive.Query{}); len(got) != 0 {
+		t.Errorf("expected the old title to be gone from the index, got %+v", got)
+	}
+	if got, _ := a.Entries(archive.Query{}); got[0].VisitCount != 3 {
+		t.Errorf("expected the visit count to be refreshed, got %+v", got[0])
+	}
+}
@@ -0,0 +1,106 @@
+package parse_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/akshatsrivastava11/Histograph/internals/parse"
+	"github.com/akshatsrivastava11/Histograph/internals/render"
+	"github.com/akshatsrivastava11/Histograph/internals/types"
+)
+
+func TestChromeTimeToUnix(t *testing.T) {
+	tests := []struct {
+		name   string
+		webkit int64
+		want   time.Time
+	}{
+		{"webkit epoch", 0, time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"unix epoch", 11644473600000000, time.Unix(0, 0).UTC()},
+		{"microsecond after unix epoch", 11644473600000001, time.Unix(0, 1000).UTC()},
+		{"microsecond before unix epoch", 11644473599999999, time.Date(1969, 12, 31, 23, 59, 59, 999999000, time.UTC)},
+		{"keeps sub-second precision", 13379755200123456, time.Date(2024, 12, 27, 6, 40, 0, 123456000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parse.ChromeTimeToUnix(tt.webkit)
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("expected UTC, got %s", got.Location())
+			}
+		})
+	}
+}
+
+func TestChromeTimeToUnix_OrdersVisitsWithinASecond(t *testing.T) {
+	first := parse.ChromeTimeToUnix(13380000000000001)
+	second := parse.ChromeTimeToUnix(13380000000000002)
+	if !second.After(first) {
+		t.Errorf("expected %s to be after %s", second, first)
+	}
+}
+
+func TestFirefoxTimeToUnix(t *testing.T) {
+	tests := []struct {
+		name   string
+		prtime int64
+		want   time.Time
+	}{
+		{"unix epoch", 0, time.Unix(0, 0).UTC()},
+		{"microsecond before unix epoch", -1, time.Date(1969, 12, 31, 23, 59, 59, 999999000, time.UTC)},
+		{"keeps sub-second precision", 1735281600123456, time.Date(2024, 12, 27, 6, 40, 0, 123456000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parse.FirefoxTimeToUnix(tt.prtime)
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("expected UTC, got %s", got.Location())
+			}
+		})
+	}
+}
+
+func TestGroupByDate_DSTBoundaries(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		loc  *time.Location
+		want string
+	}{
+		// 2025-03-09 02:00 EST jumps to 03:00 EDT.
+		{"before spring forward", time.Date(2025, 3, 9, 6, 59, 59, 999999000, time.UTC), newYork, "2025-03-09"},
+		{"after spring forward", time.Date(2025, 3, 9, 7, 0, 0, 0, time.UTC), newYork, "2025-03-09"},
+		{"last EST midnight", time.Date(2025, 3, 9, 4, 59, 59, 0, time.UTC), newYork, "2025-03-08"},
+		// 2025-11-02 02:00 EDT falls back to 01:00 EST, so 01:30 happens twice.
+		{"first 01:30 on fall back", time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC), newYork, "2025-11-02"},
+		{"second 01:30 on fall back", time.Date(2025, 11, 2, 6, 30, 0, 0, time.UTC), newYork, "2025-11-02"},
+		{"last EDT midnight", time.Date(2025, 11, 2, 3, 59, 59, 0, time.UTC), newYork, "2025-11-01"},
+		{"utc ignores local offset", time.Date(2025, 11, 2, 3, 59, 59, 0, time.UTC), time.UTC, "2025-11-02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := types.VisitEntry{VisitTime: parse.ChromeTimeToUnix(tt.at.UnixMicro() + 11644473600000000)}
+			dates, groups := render.GroupByDate([]types.VisitEntry{entry}, tt.loc)
+			if len(dates) != 1 || dates[0] != tt.want {
+				t.Fatalf("expected date %s, got %v", tt.want, dates)
+			}
+			if len(groups[tt.want]) != 1 {
+				t.Errorf("expected one entry on %s, got %d", tt.want, len(groups[tt.want]))
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package parse_test
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/akshatsrivastava11/Histograph/internals/stats"
+	"github.com/akshatsrivastava11/Histograph/internals/urlnorm"
+)
+
+var urlnormCases = []struct {
+	url                  string
+	scheme, host, domain string
+}{
+	{"https://User:pw@Mail.Google.COM:8443/inbox?x=1#y", "https", "mail.google.com", "google.com"},
+	{"http://www.bbc.co.uk/news", "http", "www.bbc.co.uk", "bbc.co.uk"},
+	{"https://someone.github.io/blog/", "https", "someone.github.io", "someone.github.io"},
+	{"http://xn--mnchen-3ya.de/", "http", "münchen.de", "münchen.de"},
+	{"https://shop.xn--mnchen-3ya.de/", "https", "shop.münchen.de", "münchen.de"},
+	{"https://example.com./", "https", "example.com", "example.com"},
+	{"http://192.168.1.1:8080/admin", "http", "192.168.1.1", "192.168.1.1"},
+	{"http://[::1]:3000/", "http", "::1", "::1"},
+	{"http://localhost:8080/", "http", "localhost", "localhost"},
+	{"https://co.uk/", "https", "co.uk", "co.uk"},
+	{"http://a.b.ck/", "http", "a.b.ck", "a.b.ck"}, // *.ck
+	{"http://www.ck/", "http", "www.ck", "www.ck"}, // !www.ck
+	{"http://intranet.corp/", "http", "intranet.corp", "intranet.corp"},
+	{"http://Example.com/%zz", "http", "example.com", "example.com"},
+	{"file:///etc/hosts", "file", "", ""},
+	{"about:blank", "about", "", ""},
+	{"chrome://settings/privacy", "chrome", "settings", "settings"},
+	{"", "", "", ""},
+}
+
+func TestURLNorm_Parse(t *testing.T) {
+	for _, c := range urlnormCases {
+		p := urlnorm.Parse(c.url)
+		if p.Scheme != c.scheme || p.Host != c.host || p.Domain != c.domain {
+			t.Errorf("Parse(%q) = %+v, expected scheme %q, host %q, domain %q", c.url, p, c.scheme, c.host, c.domain)
+		}
+	}
+}
+
+func TestURLNorm_PublicSuffix(t *testing.T) {
+	cases := map[string]string{
+		"mail.google.com":   "com",
+		"bbc.co.uk":         "co.uk",
+		"someone.github.io": "github.io",
+		"a.b.ck":            "b.ck",
+		"www.ck":            "ck",
+		"intranet.corp":     "corp",
+	}
+	for host, want := range cases {
+		if got := urlnorm.PublicSuffix(host); got != want {
+			t.Errorf("PublicSuffix(%q) = %q, expected %q", host, got, want)
+		}
+	}
+	if got := urlnorm.RegistrableDomain("co.uk"); got != "" {
+		t.Errorf("expected a public suffix to have no registrable domain, got %q", got)
+	}
+}
+
+func TestSiteOf(t *testing.T) {
+	cases := []struct {
+		url          string
+		host, domain string
+	}{
+		{"https://mail.google.com/mail/u/0/", "mail.google.com", "google.com"},
+		{"https://www.GitHub.com:443/golang/go", "github.com", "github.com"},
+		{"https://docs.google.com/document/d/1", "docs.google.com", "google.com"},
+		{"about:blank", "about:", "about:"},
+		{"file:///home/me/notes.html", "file:", "file:"},
+		{"chrome://settings/", "chrome://settings", "chrome://settings"},
+	}
+	for _, c := range cases {
+		if got := stats.SiteOf(c.url, stats.ByHost); got != c.host {
+			t.Errorf("SiteOf(%q, ByHost) = %q, expected %q", c.url, got, c.host)
+		}
+		if got := stats.SiteOf(c.url, stats.ByDomain); got != c.domain {
+			t.Errorf("SiteOf(%q, ByDomain) = %q, expected %q", c.url, got, c.domain)
+		}
+	}
+}
+
+func FuzzURLNormParse(f *testing.F) {
+	for _, c := range urlnormCases {
+		f.Add(c.url)
+	}
+	f.Add("http://xn--/")
+	f.Add("http://xn--99999999999999999999999999a.com/")
+	f.Add("http://..../")
+
+	f.Fuzz(func(t *testing.T, rawURL string) {
+		p := urlnorm.Parse(rawURL)
+		if (p.Host == "") != (p.Domain == "") {
+			t.Fatalf("Parse(%q) = %+v: host and domain must both be set or empty", rawURL, p)
+		}
+		if !strings.HasSuffix(p.Host, p.Domain) {
+			t.Fatalf("Parse(%q) = %+v: domain is not a suffix of host", rawURL, p)
+		}
+		if p.Scheme != strings.ToLower(p.Scheme) {
+			t.Fatalf("Parse(%q) = %+v: scheme is not lower case", rawURL, p)
+		}
+		if p != urlnorm.Parse(rawURL) {
+			t.Fatalf("Parse(%q) is not deterministic", rawURL)
+		}
+		stats.SiteOf(rawURL, stats.ByDomain)
+	})
+}
+
+func FuzzURLNormPunycode(f *testing.F) {
+	f.Add("mnchen-3ya")
+	f.Add("wgv71a119e")
+	f.Add("-")
+	f.Fuzz(func(t *testing.T, label string) {
+		if strings.Trim(strings.ToLower(label), "abcdefghijklmnopqrstuvwxyz0123456789-") != "" {
+			t.Skip("not a host label")
+		}
+		p := urlnorm.Parse("https://xn--" + label + ".example/")
+		if !strings.HasSuffix(p.Host, ".example") || !utf8.ValidString(p.Host) {
+			t.Fatalf("unexpected host %q for label %q", p.Host, label)
+		}
+		for _, r := range p.Host {
+			if !unicode.IsPrint(r) {
+				t.Fatalf("unprintable host %q for label %q", p.Host, label)
+			}
+		}
+	})
+}